@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -27,12 +28,12 @@ func TestRootPickerPrintsSelectedWorktreePath(t *testing.T) {
 	root := newRootCommand(commandDependencies{
 		getwd:       func() (string, error) { return repoPath, nil },
 		interactive: func() bool { return true },
-		pick: func(prompt string, items []picker.Item) (string, error) {
+		pick: func(prompt string, items []picker.Item, _ bool, _ ...picker.Action) (picker.Selection, error) {
 			picked = true
 			if prompt != "worktree > " || len(items) != 2 {
 				t.Fatalf("picker = %q %#v", prompt, items)
 			}
-			return canonicalV2Path(t, linkedPath), nil
+			return picker.Selection{Key: canonicalV2Path(t, linkedPath)}, nil
 		},
 	})
 	stdout, _, err := executeV2(root)
@@ -70,7 +71,7 @@ func TestRootPickerShowsCompactWorktreesInRecentOrder(t *testing.T) {
 			markedPath = path
 			return nil
 		},
-		pick: func(prompt string, items []picker.Item) (string, error) {
+		pick: func(prompt string, items []picker.Item, _ bool, _ ...picker.Action) (picker.Selection, error) {
 			if prompt != "worktree > " || len(items) != 3 {
 				t.Fatalf("picker = %q %#v", prompt, items)
 			}
@@ -85,7 +86,7 @@ func TestRootPickerShowsCompactWorktreesInRecentOrder(t *testing.T) {
 					t.Fatalf("picker label = %q, want repository column", item.Label)
 				}
 			}
-			return recentPath, nil
+			return picker.Selection{Key: recentPath}, nil
 		},
 	})
 
@@ -123,7 +124,7 @@ func TestRootPickerFallsBackToNewestWorktreeCreation(t *testing.T) {
 		interactive: func() bool { return true },
 		lastVisited: func(string) (time.Time, bool) { return time.Time{}, false },
 		markVisited: func(string) error { return nil },
-		pick: func(_ string, items []picker.Item) (string, error) {
+		pick: func(_ string, items []picker.Item, _ bool, _ ...picker.Action) (picker.Selection, error) {
 			olderIndex, newerIndex := -1, -1
 			for index, item := range items {
 				switch item.Key {
@@ -136,7 +137,7 @@ func TestRootPickerFallsBackToNewestWorktreeCreation(t *testing.T) {
 			if newerIndex == -1 || olderIndex == -1 || newerIndex > olderIndex {
 				t.Fatalf("picker items = %#v, want newer creation before older", items)
 			}
-			return newerPath, nil
+			return picker.Selection{Key: newerPath}, nil
 		},
 	})
 
@@ -176,11 +177,11 @@ func TestRootPickerVisitOrderWinsOverCreationMetadata(t *testing.T) {
 			}
 		},
 		markVisited: func(string) error { return nil },
-		pick: func(_ string, items []picker.Item) (string, error) {
+		pick: func(_ string, items []picker.Item, _ bool, _ ...picker.Action) (picker.Selection, error) {
 			if items[0].Key != linkedPath {
 				t.Fatalf("first picker item = %#v, want most recently visited worktree", items[0])
 			}
-			return linkedPath, nil
+			return picker.Selection{Key: linkedPath}, nil
 		},
 	})
 
@@ -211,7 +212,7 @@ func TestRootPickerDoesNotTreatMainGitMtimeAsCreation(t *testing.T) {
 		interactive: func() bool { return true },
 		lastVisited: func(string) (time.Time, bool) { return time.Time{}, false },
 		markVisited: func(string) error { return nil },
-		pick: func(_ string, items []picker.Item) (string, error) {
+		pick: func(_ string, items []picker.Item, _ bool, _ ...picker.Action) (picker.Selection, error) {
 			mainIndex, linkedIndex := -1, -1
 			for index, item := range items {
 				switch item.Key {
@@ -224,7 +225,7 @@ func TestRootPickerDoesNotTreatMainGitMtimeAsCreation(t *testing.T) {
 			if linkedIndex == -1 || mainIndex == -1 || linkedIndex > mainIndex {
 				t.Fatalf("picker items = %#v, want dated linked worktree before unranked main", items)
 			}
-			return linkedPath, nil
+			return picker.Selection{Key: linkedPath}, nil
 		},
 	})
 
@@ -294,9 +295,9 @@ func TestCdExactSelectorNeverCallsPicker(t *testing.T) {
 	root := newRootCommand(commandDependencies{
 		getwd:       func() (string, error) { return repoPath, nil },
 		interactive: func() bool { return true },
-		pick: func(string, []picker.Item) (string, error) {
+		pick: func(string, []picker.Item, bool, ...picker.Action) (picker.Selection, error) {
 			t.Fatal("picker called for exact selector")
-			return "", nil
+			return picker.Selection{}, nil
 		},
 	})
 
@@ -309,6 +310,251 @@ func TestCdExactSelectorNeverCallsPicker(t *testing.T) {
 	}
 }
 
+func TestRootPickerActionsGoThroughCommandChecks(t *testing.T) {
+	repoPath := initV2Repo(t)
+	linkedPath := filepath.Join(t.TempDir(), "linked")
+	runV2Git(t, repoPath, "worktree", "add", "-b", "fix/dirty", linkedPath)
+	if err := os.WriteFile(filepath.Join(linkedPath, "dirty"), []byte("dirty"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeV2Config(t, repoPath, "")
+	linkedPath = canonicalV2Path(t, linkedPath)
+	// marked mirrors the picker's mark on linkedPath, so labels can be checked.
+	var marked bool
+	pickWith := func(selections ...picker.Selection) *cobra.Command {
+		marked = false
+		return newRootCommand(commandDependencies{
+			getwd:       func() (string, error) { return repoPath, nil },
+			interactive: func() bool { return true },
+			markVisited: func(string) error { return nil },
+			pick: func(prompt string, items []picker.Item, preview bool, actions ...picker.Action) (picker.Selection, error) {
+				if confirming := strings.HasPrefix(prompt, "remove "); confirming == (len(actions) != 0) || confirming == preview {
+					t.Fatalf("picker %q actions = %#v, preview %v", prompt, actions, preview)
+				}
+				for _, item := range items {
+					if item.Key == linkedPath && marked != strings.HasSuffix(item.Label, "(marked for removal)") {
+						t.Fatalf("picker item %#v, marked %v", item, marked)
+					}
+				}
+				if len(selections) == 0 {
+					return picker.Selection{}, picker.ErrCancelled
+				}
+				selection := selections[0]
+				selections = selections[1:]
+				if selection.Key == linkedPath && selection.Action == navigateMark {
+					marked = !marked
+				}
+				return selection, nil
+			},
+		})
+	}
+
+	stdout, stderr, err := executeV2(pickWith(
+		picker.Selection{Key: linkedPath, Action: navigateLock},
+		picker.Selection{Key: linkedPath},
+	))
+	if err != nil || stdout != linkedPath+"\n" || !strings.Contains(stderr, "Locked app:fix/dirty") {
+		t.Fatalf("lock then pick = %v, stdout %q, stderr %q", err, stdout, stderr)
+	}
+	// A bare ctrl-x only marks; backing out of the confirmation or the picker
+	// removes nothing.
+	markLinked := picker.Selection{Key: linkedPath, Action: navigateMark}
+	if _, _, err := executeV2(pickWith(markLinked)); !errors.Is(err, picker.ErrCancelled) {
+		t.Fatalf("mark then cancel = %v", err)
+	}
+	if _, _, err := executeV2(pickWith(markLinked, picker.Selection{Key: repoPath}, picker.Selection{Key: "back"})); !errors.Is(err, picker.ErrCancelled) {
+		t.Fatalf("mark then back out = %v", err)
+	}
+	if _, err := os.Stat(linkedPath); err != nil {
+		t.Fatalf("marked worktree removed without confirmation: %v", err)
+	}
+	_, _, err = executeV2(pickWith(markLinked, picker.Selection{Key: repoPath}, picker.Selection{Key: "remove"}))
+	if err == nil || !strings.Contains(err.Error(), "worktree is locked") {
+		t.Fatalf("remove locked worktree error = %v", err)
+	}
+	if _, stderr, err := executeV2(pickWith(picker.Selection{Key: linkedPath, Action: navigateLock})); !errors.Is(err, picker.ErrCancelled) || !strings.Contains(stderr, "Unlocked app:fix/dirty") {
+		t.Fatalf("unlock = %v, stderr %q", err, stderr)
+	}
+	_, _, err = executeV2(pickWith(markLinked, picker.Selection{Key: repoPath}, picker.Selection{Key: "remove"}))
+	if err == nil || !strings.Contains(err.Error(), "--discard") {
+		t.Fatalf("remove dirty worktree error = %v", err)
+	}
+
+	stdout, _, err = executeV2(pickWith(picker.Selection{Action: navigateNew, Query: "from-picker"}))
+	if err != nil {
+		t.Fatalf("new from picker error = %v", err)
+	}
+	createdPath := strings.TrimSpace(stdout)
+	if branch := strings.TrimSpace(v2GitOutput(t, createdPath, "branch", "--show-current")); branch != "feat/from-picker" {
+		t.Fatalf("new from picker branch = %q", branch)
+	}
+	stdout, _, err = executeV2(pickWith(picker.Selection{Key: createdPath, Action: navigateMark}, picker.Selection{Key: createdPath}, picker.Selection{Key: "remove"}))
+	if err != nil || stdout != canonicalV2Path(t, repoPath)+"\n" {
+		t.Fatalf("remove from picker = %v, stdout %q", err, stdout)
+	}
+	if _, err := os.Stat(createdPath); !os.IsNotExist(err) {
+		t.Fatalf("removed worktree still exists: %v", err)
+	}
+}
+
+func TestOnlyWorktreePickersPreview(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	trashedPath, _, err := executeV2(root, "new", "trashed")
+	if err != nil {
+		t.Fatalf("new error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(strings.TrimSpace(trashedPath), "draft"), []byte("draft"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "--discard", "feat/trashed"); err != nil {
+		t.Fatalf("rm --discard error = %v", err)
+	}
+	if _, _, err := executeV2(newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }}), "new", "kept"); err != nil {
+		t.Fatalf("new error = %v", err)
+	}
+	unregistered := initV2Repo(t)
+
+	previews := make(map[string]bool)
+	for _, args := range [][]string{nil, {"rm"}, {"restore"}, {"scan", filepath.Dir(unregistered)}} {
+		root := newRootCommand(commandDependencies{
+			getwd:       func() (string, error) { return repoPath, nil },
+			interactive: func() bool { return true },
+			pick: func(prompt string, _ []picker.Item, preview bool, _ ...picker.Action) (picker.Selection, error) {
+				previews[prompt] = preview
+				return picker.Selection{}, picker.ErrCancelled
+			},
+			pickMany: func(prompt string, _ []picker.Item, preview bool) ([]string, error) {
+				previews[prompt] = preview
+				return nil, picker.ErrCancelled
+			},
+		})
+		if _, _, err := executeV2(root, args...); !errors.Is(err, picker.ErrCancelled) {
+			t.Fatalf("%v error = %v", args, err)
+		}
+	}
+	want := map[string]bool{"worktree > ": true, "remove > ": true, "restore > ": false, "register > ": false}
+	if !reflect.DeepEqual(previews, want) {
+		t.Fatalf("picker previews = %v, want %v", previews, want)
+	}
+}
+
+func TestFuzzySelectorsMatchLooselyAndPickAmongTies(t *testing.T) {
+	repoPath := initV2Repo(t)
+	base := t.TempDir()
+	authPath := filepath.Join(base, "auth")
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/auth", authPath)
+	runV2Git(t, repoPath, "worktree", "add", "-b", "fix/login", filepath.Join(base, "login"))
+	runV2Git(t, repoPath, "worktree", "add", "-b", "fix/logout", filepath.Join(base, "logout"))
+	writeV2Config(t, repoPath, "")
+	authPath = canonicalV2Path(t, authPath)
+	dependencies := commandDependencies{
+		getwd:       func() (string, error) { return repoPath, nil },
+		interactive: func() bool { return false },
+		markVisited: func(string) error { return nil },
+	}
+
+	if _, _, err := executeV2(newRootCommand(dependencies), "cd", "auth"); err == nil {
+		t.Fatal("exact selector auth resolved without --fuzzy")
+	}
+	for _, args := range [][]string{{"cd", "--fuzzy", "auth"}, {"cd", "~auth"}, {"~AUTH"}} {
+		stdout, _, err := executeV2(newRootCommand(dependencies), args...)
+		if err != nil || stdout != authPath+"\n" {
+			t.Fatalf("%v = %v, stdout %q", args, err, stdout)
+		}
+	}
+	_, _, err := executeV2(newRootCommand(dependencies), "cd", "~log")
+	if err == nil || !strings.Contains(err.Error(), "app:fix/login") || !strings.Contains(err.Error(), "app:fix/logout") {
+		t.Fatalf("ambiguous non-interactive error = %v", err)
+	}
+
+	dependencies.interactive = func() bool { return true }
+	dependencies.pick = func(prompt string, items []picker.Item, _ bool, _ ...picker.Action) (picker.Selection, error) {
+		if prompt != "~log > " || len(items) != 2 {
+			t.Fatalf("picker = %q %#v", prompt, items)
+		}
+		for _, item := range items {
+			if strings.HasSuffix(item.Label, "fix/logout") {
+				return picker.Selection{Key: item.Key}, nil
+			}
+		}
+		t.Fatalf("picker items = %#v", items)
+		return picker.Selection{}, nil
+	}
+	stdout, _, err := executeV2(newRootCommand(dependencies), "rm", "--fuzzy", "log")
+	if err != nil || stdout != canonicalV2Path(t, repoPath)+"\n" {
+		t.Fatalf("rm --fuzzy log = %v, stdout %q", err, stdout)
+	}
+	if _, err := os.Stat(filepath.Join(base, "logout")); !os.IsNotExist(err) {
+		t.Fatalf("picked worktree still exists: %v", err)
+	}
+	if _, _, err := executeV2(newRootCommand(dependencies), "rm", "--fuzzy", "--merged"); err == nil || !strings.Contains(err.Error(), "--fuzzy") {
+		t.Fatalf("rm --fuzzy --merged error = %v", err)
+	}
+}
+
+func TestRecentSelectorsCountBackThroughVisits(t *testing.T) {
+	repoPath := initV2Repo(t)
+	otherRepo := initV2Repo(t)
+	base := t.TempDir()
+	firstPath, secondPath, gonePath := filepath.Join(base, "first"), filepath.Join(base, "second"), filepath.Join(base, "gone")
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/first", firstPath)
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/second", secondPath)
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/gone", gonePath)
+	writeV2Config(t, repoPath, "  - path: "+otherRepo+"\n    name: web\n")
+	firstPath, secondPath = canonicalV2Path(t, firstPath), canonicalV2Path(t, secondPath)
+	from := func(directory string) *cobra.Command {
+		return newRootCommand(commandDependencies{getwd: func() (string, error) { return directory, nil }})
+	}
+
+	if _, _, err := executeV2(from(repoPath), "cd", "-"); err == nil || !strings.Contains(err.Error(), "no previously visited") {
+		t.Fatalf("cd - without history error = %v", err)
+	}
+	for _, selector := range []string{"app:feat/first", "app:feat/gone", "web:", "app:feat/second"} {
+		if _, _, err := executeV2(from(repoPath), "cd", selector); err != nil {
+			t.Fatalf("cd %s error = %v", selector, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	runV2Git(t, repoPath, "worktree", "remove", gonePath)
+
+	// JSON navigation leaves the history alone, so every lookup sees the same
+	// visits: second (the current worktree), web, gone (removed), then first.
+	for _, test := range []struct{ selector, want string }{
+		{"-", otherRepo},
+		{"@-1", otherRepo},
+		{"@-2", firstPath},
+		{"app:@-1", firstPath},
+	} {
+		stdout, _, err := executeV2(from(secondPath), "--json", "cd", test.selector)
+		var output worktreeOutput
+		if err != nil || json.Unmarshal([]byte(stdout), &output) != nil || output.Path != canonicalV2Path(t, test.want) {
+			t.Fatalf("cd %s = %v, stdout %q, want %s", test.selector, err, stdout, test.want)
+		}
+	}
+	if _, _, err := executeV2(from(secondPath), "cd", "app:@-2"); err == nil || !strings.Contains(err.Error(), "only 1 worktree") {
+		t.Fatalf("cd app:@-2 error = %v", err)
+	}
+	// Other commands taking a selector count back the same way.
+	lockOut, _, err := executeV2(from(secondPath), "--json", "lock", "app:@-1")
+	var locked lockOutput
+	if err != nil || json.Unmarshal([]byte(lockOut), &locked) != nil || len(locked.Worktrees) != 1 || locked.Worktrees[0].Path != firstPath {
+		t.Fatalf("lock app:@-1 = %v, stdout %q", err, lockOut)
+	}
+	if _, stderr, err := executeV2(from(secondPath), "unlock", "app:@-1"); err != nil {
+		t.Fatalf("unlock app:@-1 = %v, stderr %q", err, stderr)
+	}
+	if preview, _, err := executeV2(from(secondPath), "--color=never", "__preview", "app:@-1"); err != nil || !strings.HasPrefix(preview, "app:feat/first\n") {
+		t.Fatalf("__preview app:@-1 = %v, stdout %q", err, preview)
+	}
+	stdout, _, err := executeV2(from(repoPath), "cd", "-")
+	if err != nil || stdout != secondPath+"\n" {
+		t.Fatalf("cd - from main = %v, stdout %q", err, stdout)
+	}
+}
+
 func TestDeletedConfigRepositoryWarnsWithoutBlockingCommand(t *testing.T) {
 	repoPath := initV2Repo(t)
 	writeV2Config(t, repoPath, "  - path: /definitely/deleted/grove-repo\n    name: deleted\n")
@@ -358,6 +604,332 @@ func TestNewCreatesCanonicalNestedWorktreeAndAutoRegisters(t *testing.T) {
 	}
 }
 
+func TestNewFromStartsBranchAtRequestedPoint(t *testing.T) {
+	repoPath := initV2Repo(t)
+	runV2Git(t, repoPath, "tag", "v1")
+	releaseCommit := v2GitOutput(t, repoPath, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(repoPath, "later"), []byte("later"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runV2Git(t, repoPath, "add", "later")
+	runV2Git(t, repoPath, "commit", "-m", "later")
+	writeV2Config(t, repoPath, "")
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "new", "fix/hot", "--from", "v1")
+	if err != nil {
+		t.Fatalf("new --from error = %v", err)
+	}
+	var output newOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if output.StartPoint != "v1" || !output.Created {
+		t.Fatalf("output = %#v", output)
+	}
+	if got := v2GitOutput(t, output.Path, "rev-parse", "HEAD"); got != releaseCommit {
+		t.Fatalf("HEAD = %s, want tag commit %s", got, releaseCommit)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--json", "new", "fix/hot-followup", "--from", "app:fix/hot")
+	if err != nil {
+		t.Fatalf("new --from selector error = %v", err)
+	}
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if output.StartPoint != "refs/heads/fix/hot" || v2GitOutput(t, output.Path, "rev-parse", "HEAD") != releaseCommit {
+		t.Fatalf("selector output = %#v", output)
+	}
+
+	for _, test := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"new", "fix/nope", "--from", "does-not-exist"}, want: "does not name a commit"},
+		{args: []string{"new", "fix/hot", "--from", "main"}, want: "already exists"},
+	} {
+		root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		if _, _, err := executeV2(root, test.args...); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Fatalf("%v error = %v, want %q", test.args, err, test.want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, ".wt", "fix", "nope")); !os.IsNotExist(err) {
+		t.Fatalf("invalid start point created a worktree: %v", err)
+	}
+}
+
+func TestNewPRFetchesPullRequestHeadFromRemote(t *testing.T) {
+	repoPath := initV2Repo(t)
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	runV2Git(t, repoPath, "init", "--bare", remotePath)
+	runV2Git(t, repoPath, "remote", "add", "origin", remotePath)
+	runV2Git(t, repoPath, "push", "origin", "main")
+	runV2Git(t, repoPath, "checkout", "-b", "contributor")
+	if err := os.WriteFile(filepath.Join(repoPath, "patch"), []byte("patch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runV2Git(t, repoPath, "add", "patch")
+	runV2Git(t, repoPath, "commit", "-m", "patch")
+	headCommit := v2GitOutput(t, repoPath, "rev-parse", "HEAD")
+	runV2Git(t, repoPath, "push", "origin", "HEAD:refs/pull/7/head", "HEAD:refs/merge-requests/8/head")
+	runV2Git(t, repoPath, "checkout", "main")
+	runV2Git(t, repoPath, "branch", "-D", "contributor")
+	writeV2Config(t, repoPath, "")
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "new", "--pr", "7")
+	if err != nil {
+		t.Fatalf("new --pr error = %v", err)
+	}
+	var output newOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	wantPath := filepath.Join(canonicalV2Path(t, repoPath), ".wt", "pr", "7")
+	if output.Branch != "pr/7" || output.Path != wantPath || !output.Created || output.PullRequest != 7 || output.StartPoint != "refs/pull/7/head" {
+		t.Fatalf("output = %#v", output)
+	}
+	if got := v2GitOutput(t, output.Path, "rev-parse", "HEAD"); got != headCommit {
+		t.Fatalf("HEAD = %s, want pull request head %s", got, headCommit)
+	}
+	// pr/7 tracks the pull request ref, so pulling in the worktree updates it.
+	nextCommit := v2GitOutput(t, repoPath, "commit-tree", headCommit+"^{tree}", "-p", headCommit, "-m", "follow-up")
+	runV2Git(t, repoPath, "push", "origin", nextCommit+":refs/pull/7/head")
+	runV2Git(t, output.Path, "pull", "--ff-only")
+	if got := v2GitOutput(t, output.Path, "rev-parse", "HEAD"); got != nextCommit {
+		t.Fatalf("HEAD after pull = %s, want %s", got, nextCommit)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "new", "app:", "--pr", "7")
+	if err != nil || strings.TrimSpace(stdout) != wantPath {
+		t.Fatalf("repeat new --pr = %q, %v", stdout, err)
+	}
+
+	configPath := filepath.Join(os.Getenv("HOME"), ".config", "grove", "config.yaml")
+	file, err := os.OpenFile(configPath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString("    pull_ref: refs/merge-requests/{number}/head\n"); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "new", "--pr", "8")
+	if err != nil {
+		t.Fatalf("new --pr with pull_ref error = %v", err)
+	}
+	if got := v2GitOutput(t, strings.TrimSpace(stdout), "rev-parse", "HEAD"); got != headCommit {
+		t.Fatalf("merge request HEAD = %s, want %s", got, headCommit)
+	}
+
+	for _, test := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"new", "--pr", "9"}, want: "fetching pull request 9"},
+		{args: []string{"new", "review", "--pr", "7"}, want: "names the branch itself"},
+		{args: []string{"new", "--pr", "0"}, want: "positive"},
+		{args: []string{"new", "--pr", "7", "--stack"}, want: "cannot be combined"},
+	} {
+		root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		if _, _, err := executeV2(root, test.args...); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Fatalf("%v error = %v, want %q", test.args, err, test.want)
+		}
+	}
+
+	// A worktree that cannot be created takes the fetched branch with it.
+	runV2Git(t, repoPath, "push", "origin", headCommit+":refs/merge-requests/10/head")
+	blocked := filepath.Join(repoPath, ".wt", "pr", "10")
+	if err := os.MkdirAll(blocked, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(blocked, "in-the-way"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "new", "--pr", "10"); err == nil || !strings.Contains(err.Error(), "creating worktree") {
+		t.Fatalf("blocked new --pr error = %v", err)
+	}
+	if branches := v2GitOutput(t, repoPath, "branch", "--list", "pr/10"); branches != "" {
+		t.Fatalf("failed new --pr left branch %q", branches)
+	}
+}
+
+func TestFetchUpdatesMergedCleanupAndNewBase(t *testing.T) {
+	repoPath := initV2Repo(t)
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	runV2Git(t, repoPath, "init", "--bare", remotePath)
+	runV2Git(t, repoPath, "remote", "add", "origin", remotePath)
+	runV2Git(t, repoPath, "push", "origin", "main")
+	writeV2Config(t, repoPath, "")
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	donePath, _, err := executeV2(root, "new", "done")
+	if err != nil {
+		t.Fatalf("new done error = %v", err)
+	}
+	donePath = strings.TrimSpace(donePath)
+	if err := os.WriteFile(filepath.Join(donePath, "done"), []byte("done"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runV2Git(t, donePath, "add", "done")
+	runV2Git(t, donePath, "commit", "-m", "done")
+	runV2Git(t, donePath, "push", "origin", "feat/done")
+
+	upstreamPath := filepath.Join(t.TempDir(), "upstream")
+	runV2Git(t, repoPath, "clone", "-b", "main", remotePath, upstreamPath)
+	runV2Git(t, upstreamPath, "config", "user.name", "Grove Test")
+	runV2Git(t, upstreamPath, "config", "user.email", "grove@example.test")
+	runV2Git(t, upstreamPath, "merge", "--no-ff", "-m", "merge done", "origin/feat/done")
+	runV2Git(t, upstreamPath, "push", "origin", "main")
+	upstreamHead := v2GitOutput(t, upstreamPath, "rev-parse", "HEAD")
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "rm", "--merged", "--dry-run")
+	if err != nil || strings.Contains(stdout, "app:feat/done") {
+		t.Fatalf("rm --merged without fetch = %q, %v", stdout, err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "rm", "--merged", "--dry-run", "--fetch")
+	if err != nil || !strings.Contains(stdout, "app:feat/done") {
+		t.Fatalf("rm --merged --fetch = %q, %v", stdout, err)
+	}
+	// Without --fetch only the local default branch counts, even once
+	// origin/main has the merge.
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "rm", "--merged", "--dry-run")
+	if err != nil || strings.Contains(stdout, "app:feat/done") {
+		t.Fatalf("rm --merged after fetch = %q, %v", stdout, err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "new", "other", "--from", "main", "--fetch"); err == nil || !strings.Contains(err.Error(), "cannot be combined with --from") {
+		t.Fatalf("new --from --fetch error = %v", err)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--json", "new", "fresh", "--fetch")
+	if err != nil {
+		t.Fatalf("new --fetch error = %v", err)
+	}
+	var output newOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if output.StartPoint != "refs/remotes/origin/main" || v2GitOutput(t, output.Path, "rev-parse", "HEAD") != upstreamHead {
+		t.Fatalf("new --fetch output = %#v", output)
+	}
+	upstream := exec.Command("git", "rev-parse", "--abbrev-ref", "feat/fresh@{upstream}")
+	upstream.Dir = output.Path
+	if out, err := upstream.CombinedOutput(); err == nil {
+		t.Fatalf("branch started from origin/main tracks %s", out)
+	}
+
+	runV2Git(t, repoPath, "remote", "set-url", "origin", filepath.Join(t.TempDir(), "gone.git"))
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	_, stderr, err := executeV2(root, "new", "offline", "--fetch")
+	if err != nil || !strings.Contains(stderr, "warning: repo app: fetching origin") {
+		t.Fatalf("new --fetch with unreachable remote = %v, stderr %q", err, stderr)
+	}
+}
+
+func TestConfiguredRemoteReplacesOrigin(t *testing.T) {
+	repoPath := initV2Repo(t)
+	remotePath := filepath.Join(t.TempDir(), "upstream.git")
+	runV2Git(t, repoPath, "init", "--bare", remotePath)
+	runV2Git(t, repoPath, "remote", "add", "upstream", remotePath)
+	runV2Git(t, repoPath, "push", "upstream", "main", "main:feat/shared", "main:refs/pull/3/head")
+	writeV2Config(t, repoPath, "")
+	configPath := filepath.Join(os.Getenv("HOME"), ".config", "grove", "config.yaml")
+	file, err := os.OpenFile(configPath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString("    remote: upstream\n"); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, stderr, err := executeV2(root, "new", "shared", "--fetch")
+	if err != nil || stderr != "" {
+		t.Fatalf("new --fetch = %v, stderr %q", err, stderr)
+	}
+	if got := v2GitOutput(t, strings.TrimSpace(stdout), "rev-parse", "--abbrev-ref", "@{upstream}"); got != "upstream/feat/shared" {
+		t.Fatalf("upstream = %q, want upstream/feat/shared", got)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "new", "--pr", "3"); err != nil {
+		t.Fatalf("new --pr error = %v", err)
+	}
+}
+
+func TestNewStackRecordsParentForListAndMergedCleanup(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	parentPath, _, err := executeV2(root, "new", "auth")
+	if err != nil {
+		t.Fatalf("new auth error = %v", err)
+	}
+	parentPath = strings.TrimSpace(parentPath)
+	if err := os.WriteFile(filepath.Join(parentPath, "auth"), []byte("auth"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runV2Git(t, parentPath, "add", "auth")
+	runV2Git(t, parentPath, "commit", "-m", "auth")
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return parentPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "new", "--stack", "auth-ui")
+	if err != nil {
+		t.Fatalf("new --stack error = %v", err)
+	}
+	var output newOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if output.StartPoint != "refs/heads/feat/auth" || v2GitOutput(t, output.Path, "rev-parse", "HEAD") != v2GitOutput(t, parentPath, "rev-parse", "HEAD") {
+		t.Fatalf("stacked output = %#v", output)
+	}
+	if got := v2GitOutput(t, repoPath, "config", "branch.feat/auth-ui.grove-parent"); got != "feat/auth" {
+		t.Fatalf("recorded parent = %q", got)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "new", "other"); err != nil {
+		t.Fatalf("new other error = %v", err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--color=never", "list")
+	if err != nil {
+		t.Fatalf("list error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "├── feat/other") || !strings.HasPrefix(lines[2], "└── feat/auth") || !strings.HasPrefix(lines[3], "    └── feat/auth-ui") {
+		t.Fatalf("list output:\n%s", stdout)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "rm", "--merged", "--dry-run")
+	if err != nil {
+		t.Fatalf("rm --merged error = %v", err)
+	}
+	if !strings.Contains(stdout, "app:feat/auth-ui") || !strings.Contains(stdout, "(stack-parent)") || strings.Contains(stdout, "app:feat/auth ") {
+		t.Fatalf("merged candidates = %q, want only the stacked child folded into its parent", stdout)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "new", "--stack", "from-main-repo"); err != nil {
+		t.Fatalf("new --stack from main checkout error = %v", err)
+	}
+	if got := v2GitOutput(t, repoPath, "config", "branch.feat/from-main-repo.grove-parent"); got != "main" {
+		t.Fatalf("parent from main checkout = %q", got)
+	}
+}
+
 func TestNewRegistersBeforeCreatingWorktree(t *testing.T) {
 	repoPath := initV2Repo(t)
 	home := t.TempDir()
@@ -543,7 +1115,7 @@ func TestRemovePickerSupportsMultipleWorktrees(t *testing.T) {
 	root := newRootCommand(commandDependencies{
 		getwd:       func() (string, error) { return repoPath, nil },
 		interactive: func() bool { return true },
-		pickMany: func(prompt string, items []picker.Item) ([]string, error) {
+		pickMany: func(prompt string, items []picker.Item, _ bool) ([]string, error) {
 			if prompt != "remove > " || len(items) != 2 {
 				t.Fatalf("picker = %q %#v", prompt, items)
 			}
@@ -582,7 +1154,7 @@ func TestRemoveMultiplePreflightsEveryTargetBeforeDeleting(t *testing.T) {
 	root := newRootCommand(commandDependencies{
 		getwd:       func() (string, error) { return repoPath, nil },
 		interactive: func() bool { return true },
-		pickMany: func(string, []picker.Item) ([]string, error) {
+		pickMany: func(string, []picker.Item, bool) ([]string, error) {
 			return []string{canonicalV2Path(t, firstPath), canonicalV2Path(t, secondPath)}, nil
 		},
 	})
@@ -1133,6 +1705,115 @@ func TestRemoveMergedJSONDryRunReportsWouldRemove(t *testing.T) {
 	}
 }
 
+func TestRemoveMergedSquashLabelsMatchedRule(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	for _, branch := range []string{"squashed", "kept"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		path, _, err := executeV2(root, "new", branch)
+		if err != nil {
+			t.Fatalf("new %s error = %v", branch, err)
+		}
+		path = strings.TrimSpace(path)
+		for _, name := range []string{"one", "two"} {
+			if err := os.WriteFile(filepath.Join(path, branch+"-"+name), []byte(name), 0644); err != nil {
+				t.Fatal(err)
+			}
+			runV2Git(t, path, "add", ".")
+			runV2Git(t, path, "commit", "-m", name)
+		}
+	}
+	runV2Git(t, repoPath, "merge", "--squash", "feat/squashed")
+	runV2Git(t, repoPath, "commit", "-m", "squash feat/squashed")
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "rm", "--merged", "--dry-run")
+	if err != nil || strings.Contains(stdout, "feat/squashed") {
+		t.Fatalf("ancestry-only dry run = %q, %v", stdout, err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "rm", "--merged=squash", "--dry-run")
+	if err != nil || !strings.Contains(stdout, "app:feat/squashed") || !strings.HasSuffix(strings.TrimSpace(stdout), "(squash)") || strings.Contains(stdout, "feat/kept") {
+		t.Fatalf("squash dry run = %q, %v", stdout, err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--json", "rm", "--merged=squash")
+	if err != nil {
+		t.Fatalf("rm --merged=squash error = %v", err)
+	}
+	var output removeOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.Removed) != 1 || output.Removed[0].Selector != "app:feat/squashed" || output.Removed[0].Rule != "squash" {
+		t.Fatalf("removed = %#v", output.Removed)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "--merged=rebase"); err == nil || !strings.Contains(err.Error(), "invalid --merged") {
+		t.Fatalf("rm --merged=rebase error = %v", err)
+	}
+}
+
+func TestRemoveDeleteBranchReportsEachBranch(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	for _, branch := range []string{"merged", "open", "forced", "bulk"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		path, _, err := executeV2(root, "new", branch)
+		if err != nil {
+			t.Fatalf("new %s error = %v", branch, err)
+		}
+		if branch == "merged" || branch == "bulk" {
+			continue
+		}
+		path = strings.TrimSpace(path)
+		if err := os.WriteFile(filepath.Join(path, branch), []byte(branch), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runV2Git(t, path, "add", branch)
+		runV2Git(t, path, "commit", "-m", branch)
+	}
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "rm", "--delete-branch", "feat/merged", "feat/open")
+	if err != nil {
+		t.Fatalf("rm --delete-branch error = %v", err)
+	}
+	var output removeOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.Removed) != 2 ||
+		output.Removed[0].Branch != "feat/merged" || output.Removed[0].BranchStatus != "deleted" || output.Removed[0].BranchReason != "merged (ancestor)" ||
+		output.Removed[1].Branch != "feat/open" || output.Removed[1].BranchStatus != "refused" || !strings.Contains(output.Removed[1].BranchReason, "--discard") {
+		t.Fatalf("removed = %#v", output.Removed)
+	}
+	branches := v2GitOutput(t, repoPath, "branch", "--list", "feat/*")
+	if strings.Contains(branches, "feat/merged") || !strings.Contains(branches, "feat/open") {
+		t.Fatalf("branches after rm = %q", branches)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	_, stderr, err := executeV2(root, "rm", "--delete-branch", "--discard", "feat/forced")
+	if err != nil || !strings.Contains(stderr, "deleted branch feat/forced") {
+		t.Fatalf("rm --delete-branch --discard = %v, stderr %q", err, stderr)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--json", "rm", "--merged", "--delete-branch", "--dry-run")
+	if err != nil {
+		t.Fatalf("rm --merged --delete-branch --dry-run error = %v", err)
+	}
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.WouldRemove) != 1 || output.WouldRemove[0].BranchStatus != "deleted" {
+		t.Fatalf("would remove = %#v", output.WouldRemove)
+	}
+	if branches := v2GitOutput(t, repoPath, "branch", "--list", "feat/*"); strings.Contains(branches, "feat/forced") || !strings.Contains(branches, "feat/bulk") {
+		t.Fatalf("branches after dry run = %q", branches)
+	}
+}
+
 func TestRemoveMergedRejectsNullOutputBeforeMutation(t *testing.T) {
 	repoPath := initV2Repo(t)
 	linkedPath := filepath.Join(t.TempDir(), "merged")
@@ -1204,9 +1885,64 @@ func TestListJSONIsVersionedAndStatusIsOptIn(t *testing.T) {
 	if err != nil {
 		t.Fatalf("list --status error = %v", err)
 	}
-	if !strings.Contains(stdout, "\"dirty\": true") {
+	if !strings.Contains(stdout, "\"dirty\": true") || !strings.Contains(stdout, "\"untracked\": 1") || !strings.Contains(stdout, "\"staged\": 0") {
 		t.Fatalf("status JSON = %s", stdout)
 	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }, interactive: func() bool { return false }})
+	stdout, _, err = executeV2(root, "--color=never", "list", "--status")
+	if err != nil {
+		t.Fatalf("list --status error = %v", err)
+	}
+	if !strings.Contains(stdout, "chore/deps  [1 untracked]") {
+		t.Fatalf("status output = %q", stdout)
+	}
+}
+
+func TestListParallelStatusPreservesGitOrder(t *testing.T) {
+	repoPath := initV2Repo(t)
+	worktreeRoot := t.TempDir()
+	for index, branch := range []string{"feat/a", "feat/b", "feat/c", "feat/d", "feat/e"} {
+		path := filepath.Join(worktreeRoot, branch)
+		runV2Git(t, repoPath, "worktree", "add", "-b", branch, path)
+		if index%2 == 0 {
+			if err := os.WriteFile(filepath.Join(path, "dirty"), []byte("dirty"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeV2Config(t, repoPath, "")
+
+	var outputs []string
+	for _, jobs := range []string{"1", "4"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		stdout, _, err := executeV2(root, "--json", "list", "--status", "--jobs", jobs)
+		if err != nil {
+			t.Fatalf("list --jobs %s error = %v", jobs, err)
+		}
+		outputs = append(outputs, stdout)
+	}
+	if outputs[0] != outputs[1] {
+		t.Fatalf("parallel status changed output:\n%s\n---\n%s", outputs[0], outputs[1])
+	}
+	var document listDocument
+	if err := json.Unmarshal([]byte(outputs[1]), &document); err != nil {
+		t.Fatal(err)
+	}
+	worktrees := document.Repositories[0].Worktrees
+	if len(worktrees) != 6 {
+		t.Fatalf("worktrees = %#v", worktrees)
+	}
+	for index, worktree := range worktrees[1:] {
+		if worktree.Dirty == nil || *worktree.Dirty != (index%2 == 0) || worktree.Ahead == nil || worktree.StatusError != "" {
+			t.Fatalf("worktree %s status = %#v", worktree.Branch, worktree)
+		}
+	}
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "list", "--status", "--jobs", "0"); err == nil {
+		t.Fatal("list --jobs 0 was accepted")
+	}
 }
 
 func TestListOmitsWorktreePaths(t *testing.T) {
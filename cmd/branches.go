@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"grove/internal/catalog"
+	gitx "grove/internal/git"
+	"grove/internal/inventory"
+	"grove/internal/names"
+
+	"github.com/spf13/cobra"
+)
+
+type branchesListOutput struct {
+	Version  int           `json:"version"`
+	Branches []branchEntry `json:"branches"`
+}
+
+type branchesPruneOutput struct {
+	Version     int           `json:"version"`
+	DryRun      bool          `json:"dry_run"`
+	Deleted     []branchEntry `json:"deleted"`
+	WouldDelete []branchEntry `json:"would_delete"`
+}
+
+type branchEntry struct {
+	Repository string    `json:"repository"`
+	Branch     string    `json:"branch"`
+	Commit     string    `json:"commit"`
+	LastCommit time.Time `json:"last_commit"`
+	// Grove reports a branch Grove created or named; only those are pruned.
+	Grove    bool   `json:"grove"`
+	Default  bool   `json:"default,omitempty"`
+	Worktree string `json:"worktree,omitempty"`
+	// Merged names the rule that proves the branch merged, if any.
+	Merged string `json:"merged,omitempty"`
+}
+
+// branchState is one local branch together with what cleanup needs to know.
+type branchState struct {
+	repository *catalog.Repository
+	info       gitx.BranchInfo
+	createdAt  time.Time
+	grove      bool
+	isDefault  bool
+	worktree   string
+}
+
+// activity is the later of the branch's creation and its tip commit, so a
+// branch Grove just created from an old commit does not look stale.
+func (b branchState) activity() time.Time {
+	if b.createdAt.After(b.info.CommittedAt) {
+		return b.createdAt
+	}
+	return b.info.CommittedAt
+}
+
+func (b branchState) entry() branchEntry {
+	return branchEntry{
+		Repository: b.repository.Name,
+		Branch:     b.info.Name,
+		Commit:     b.info.Commit,
+		LastCommit: b.info.CommittedAt,
+		Grove:      b.grove,
+		Default:    b.isDefault,
+		Worktree:   b.worktree,
+	}
+}
+
+type branchesPruneOptions struct {
+	merged    string
+	olderThan cleanupAge
+	dryRun    bool
+}
+
+func (a *application) branchesCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "branches",
+		Short: "List and prune local branches",
+		Args:  cobra.NoArgs,
+	}
+	command.AddCommand(a.branchesListCommand(), a.branchesPruneCommand())
+	return command
+}
+
+func (a *application) branchesListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List local branches with their worktree and merge state",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return a.runBranchesList(cmd)
+		},
+	}
+}
+
+func (a *application) branchesPruneCommand() *cobra.Command {
+	var merged, olderThanValue string
+	var dryRun bool
+	command := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete Grove-created branches that have no worktree",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			olderThan, err := parseOlderThan(olderThanValue)
+			if err != nil {
+				return err
+			}
+			merged, err = parseMergedMode(merged)
+			if err != nil {
+				return err
+			}
+			if (merged == "") == (olderThan.duration == 0) {
+				return fmt.Errorf("use exactly one of --merged or --older-than")
+			}
+			return a.runBranchesPrune(cmd, branchesPruneOptions{merged: merged, olderThan: olderThan, dryRun: dryRun})
+		},
+	}
+	command.Flags().StringVar(&merged, "merged", "", "Delete branches merged into their default branches; =squash also detects squash and rebase merges")
+	command.Flags().Lookup("merged").NoOptDefVal = mergedAncestry
+	command.Flags().StringVar(&olderThanValue, "older-than", "", "Delete branches with no activity for a duration such as 30d or 8w, merged or not")
+	command.Flags().BoolVar(&dryRun, "dry-run", false, "Preview branch deletion without deleting anything")
+	return command
+}
+
+func (a *application) runBranchesList(cmd *cobra.Command) error {
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	states := scanBranches(cmd, context.catalog.Repositories)
+	parents := stackParents{}
+	entries := make([]branchEntry, 0, len(states))
+	for _, state := range states {
+		entry := state.entry()
+		if !state.isDefault {
+			entry.Merged, _ = parents.mergedBranch(state.repository, state.info.Name, false, false)
+		}
+		entries = append(entries, entry)
+	}
+	if a.jsonOutput {
+		return writeJSON(cmd, branchesListOutput{Version: 1, Branches: entries})
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No branches.")
+		return nil
+	}
+	style := a.style(cmd.OutOrStdout())
+	now := time.Now()
+	repository := ""
+	for index, entry := range entries {
+		if entry.Repository != repository {
+			if repository != "" {
+				fmt.Fprintln(cmd.OutOrStdout())
+			}
+			repository = entry.Repository
+			fmt.Fprintln(cmd.OutOrStdout(), style.heading(repository))
+		}
+		var tags []string
+		if entry.Default {
+			tags = append(tags, "default")
+		}
+		if entry.Worktree != "" {
+			tags = append(tags, "worktree")
+		}
+		if entry.Grove {
+			tags = append(tags, "grove")
+		}
+		if entry.Merged != "" {
+			tags = append(tags, "merged")
+		}
+		suffix := "  " + relativeAge(now.Sub(states[index].activity()))
+		if len(tags) != 0 {
+			suffix += " · " + strings.Join(tags, " · ")
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s%s\n", style.branch(entry.Branch), style.muted(suffix))
+	}
+	return nil
+}
+
+func (a *application) runBranchesPrune(cmd *cobra.Command, options branchesPruneOptions) error {
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	repositories := make([]*catalog.Repository, 0, len(context.catalog.Repositories))
+	for _, repository := range context.catalog.Repositories {
+		if context.catalog.Current == repository && !context.catalog.CurrentRegistered {
+			continue
+		}
+		repositories = append(repositories, repository)
+	}
+	now := time.Now()
+	parents := stackParents{}
+	type pruneCandidate struct {
+		repository *catalog.Repository
+		entry      branchEntry
+	}
+	var candidates []pruneCandidate
+	var skips cleanupSkips
+	for _, state := range scanBranches(cmd, repositories) {
+		if state.isDefault || !state.grove {
+			continue
+		}
+		if options.olderThan.duration != 0 && now.Sub(state.activity()) < options.olderThan.duration {
+			continue
+		}
+		if state.worktree != "" {
+			skips.checkedOut++
+			continue
+		}
+		entry := state.entry()
+		rule, err := parents.mergedBranch(state.repository, state.info.Name, options.merged == mergedSquash, false)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: skipping %s:%s: %v\n", entry.Repository, entry.Branch, err)
+			skips.unsafe++
+			continue
+		}
+		if options.merged != "" && rule == "" {
+			continue
+		}
+		entry.Merged = rule
+		candidates = append(candidates, pruneCandidate{repository: state.repository, entry: entry})
+	}
+
+	results := make([]branchEntry, 0, len(candidates))
+	var failures []error
+	for _, candidate := range candidates {
+		entry := candidate.entry
+		if !options.dryRun {
+			// Git itself refuses a branch checked out by a worktree created since the scan.
+			if err := candidate.repository.Git.DeleteBranch(entry.Branch); err != nil {
+				failures = append(failures, fmt.Errorf("%s:%s: %w", entry.Repository, entry.Branch, err))
+				continue
+			}
+		}
+		results = append(results, entry)
+	}
+
+	if a.jsonOutput {
+		output := branchesPruneOutput{Version: 1, DryRun: options.dryRun, Deleted: []branchEntry{}, WouldDelete: []branchEntry{}}
+		if options.dryRun {
+			output.WouldDelete = results
+		} else {
+			output.Deleted = results
+		}
+		if err := writeJSON(cmd, output); err != nil {
+			return err
+		}
+	} else {
+		a.writeBranchesPruneText(cmd, options, results, now)
+	}
+	a.writeCleanupSkips(cmd, skips)
+	if len(failures) != 0 {
+		return errors.Join(failures...)
+	}
+	return nil
+}
+
+func (a *application) writeBranchesPruneText(cmd *cobra.Command, options branchesPruneOptions, results []branchEntry, now time.Time) {
+	scope := "merged"
+	if options.olderThan.duration != 0 {
+		scope = "older than " + options.olderThan.label
+	}
+	if len(results) == 0 {
+		if options.dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "No branches %s would be deleted.\n", scope)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "No branches %s deleted.\n", scope)
+		}
+		return
+	}
+	action := "Deleted"
+	if options.dryRun {
+		action = "Would delete"
+	}
+	style := a.style(cmd.OutOrStdout())
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %s %s %s\n", style.info(action), worktreeCount(len(results), "branch", "branches"), scope, style.muted("(tips shown for recovery):"))
+	for _, result := range results {
+		detail := shortSHA(result.Commit) + "  last commit " + relativeAge(now.Sub(result.LastCommit)) + " ago"
+		if options.olderThan.duration != 0 && result.Merged == "" {
+			detail += "  not merged"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s\n", style.branch(result.Repository+":"+result.Branch), style.muted(detail))
+	}
+}
+
+// scanBranches reads every local branch of the repositories. A repository
+// that cannot be read is reported as a warning and skipped.
+func scanBranches(cmd *cobra.Command, repositories []*catalog.Repository) []branchState {
+	var states []branchState
+	for _, repository := range repositories {
+		repositoryStates, err := scanRepositoryBranches(repository)
+		if err != nil {
+			failure := inventory.Failure{Repository: repository.Name, Err: err}
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", failure.Error())
+			continue
+		}
+		states = append(states, repositoryStates...)
+	}
+	return states
+}
+
+func scanRepositoryBranches(repository *catalog.Repository) ([]branchState, error) {
+	branches, err := repository.Git.Branches()
+	if err != nil {
+		return nil, err
+	}
+	worktrees, err := repository.Git.Worktrees()
+	if err != nil {
+		return nil, err
+	}
+	created, err := repository.Git.CreatedBranches()
+	if err != nil {
+		return nil, err
+	}
+	checkedOut := make(map[string]string, len(worktrees))
+	for _, worktree := range worktrees {
+		if worktree.Branch != "" {
+			checkedOut[worktree.Branch] = worktree.Path
+		}
+	}
+	defaultBranch := ""
+	if base, err := repository.Git.BaseRef(repository.DefaultBranch); err == nil {
+		defaultBranch = strings.TrimPrefix(strings.TrimPrefix(base, "refs/heads/"), "refs/remotes/"+repository.Git.Remote()+"/")
+	}
+	states := make([]branchState, 0, len(branches))
+	for _, branch := range branches {
+		createdAt, marked := created[branch.Name]
+		states = append(states, branchState{
+			repository: repository,
+			info:       branch,
+			createdAt:  createdAt,
+			grove:      marked || names.IsGeneratedBranch(branch.Name),
+			isDefault:  branch.Name == defaultBranch,
+			worktree:   checkedOut[branch.Name],
+		})
+	}
+	return states, nil
+}
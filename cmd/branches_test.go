@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBranchesPruneDeletesOnlyGroveBranchesWithoutWorktrees(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	for _, branch := range []string{"done", "busy", "stale"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		if _, _, err := executeV2(root, "new", branch); err != nil {
+			t.Fatalf("new %s error = %v", branch, err)
+		}
+	}
+	stalePath := filepath.Join(repoPath, ".wt", "feat", "stale")
+	if err := os.WriteFile(filepath.Join(stalePath, "stale"), []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runV2Git(t, stalePath, "add", "stale")
+	commit := exec.Command("git", "commit", "-m", "stale")
+	commit.Dir = stalePath
+	commit.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2020-01-01T00:00:00Z")
+	if out, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("commit: %s (%v)", out, err)
+	}
+	runV2Git(t, repoPath, "config", "branch.feat/stale.grove-created", "1577836800")
+	for _, branch := range []string{"done", "stale"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		if _, _, err := executeV2(root, "rm", "feat/"+branch); err != nil {
+			t.Fatalf("rm %s error = %v", branch, err)
+		}
+	}
+	runV2Git(t, repoPath, "branch", "feat/manual")
+	runV2Git(t, repoPath, "branch", "feat/07-30-cozy-otter")
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "branches", "list")
+	if err != nil {
+		t.Fatalf("branches list error = %v", err)
+	}
+	var list branchesListOutput
+	if err := json.Unmarshal([]byte(stdout), &list); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	grove := make(map[string]bool)
+	for _, branch := range list.Branches {
+		grove[branch.Branch] = branch.Grove
+	}
+	if len(list.Branches) != 6 || !grove["feat/done"] || !grove["feat/07-30-cozy-otter"] || grove["feat/manual"] || grove["main"] {
+		t.Fatalf("branches = %#v", list.Branches)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, stderr, err := executeV2(root, "--json", "branches", "prune", "--merged")
+	if err != nil {
+		t.Fatalf("branches prune --merged error = %v", err)
+	}
+	var output branchesPruneOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.Deleted) != 2 || output.Deleted[0].Branch != "feat/07-30-cozy-otter" || output.Deleted[1].Branch != "feat/done" || !strings.Contains(stderr, "1 checked out") {
+		t.Fatalf("deleted = %#v, stderr %q", output.Deleted, stderr)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "branches", "prune", "--older-than", "30d", "--dry-run")
+	if err != nil || !strings.Contains(stdout, "Would delete 1 branch older than 30d") || !strings.Contains(stdout, "app:feat/stale") || !strings.Contains(stdout, "not merged") {
+		t.Fatalf("branches prune --older-than dry run = %q, %v", stdout, err)
+	}
+	branches := v2GitOutput(t, repoPath, "branch", "--list")
+	for _, want := range []string{"feat/stale", "feat/busy", "feat/manual", "main"} {
+		if !strings.Contains(branches, want) {
+			t.Fatalf("branches after prune = %q, missing %s", branches, want)
+		}
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "branches", "prune"); err == nil || !strings.Contains(err.Error(), "exactly one") {
+		t.Fatalf("branches prune without mode error = %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"grove/internal/catalog"
+
+	"github.com/spf13/cobra"
+)
+
+// cloneResult reports one clone_dirs directory seeded into a new worktree.
+// Method is reflink, hardlink, or skipped; Source is the worktree it came from.
+type cloneResult struct {
+	Path   string `json:"path"`
+	Source string `json:"source,omitempty"`
+	Method string `json:"method"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// cloneWorktreeDirs seeds the profile's clone_dirs in a new worktree from the
+// most recently visited sibling that has them, or the main checkout. Each
+// directory is cloned with reflinks where the filesystem supports them and as
+// a tree of hard links otherwise, so caches such as node_modules are ready
+// before setup runs.
+func (a *application) cloneWorktreeDirs(cmd *cobra.Command, repository *catalog.Repository, profile *catalog.Profile, worktreePath string) []cloneResult {
+	if profile == nil || len(profile.CloneDirs) == 0 {
+		return nil
+	}
+	root, err := filepath.EvalSymlinks(worktreePath)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: cloning directories skipped: resolving worktree: %v\n", err)
+		return nil
+	}
+	sources := a.cloneSources(repository, root)
+	results := make([]cloneResult, 0)
+	for _, pattern := range profile.CloneDirs {
+		matched := false
+		for _, source := range sources {
+			matches, err := fs.Glob(os.DirFS(source), filepath.ToSlash(pattern))
+			if err != nil {
+				break
+			}
+			for _, match := range matches {
+				if reservedCheckoutPath(match) {
+					continue
+				}
+				if info, err := os.Lstat(filepath.Join(source, filepath.FromSlash(match))); err != nil || !info.IsDir() {
+					continue
+				}
+				matched = true
+				result := cloneDirectory(root, source, match)
+				if result.Method == "skipped" {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: not cloning %s: %s\n", result.Path, result.Reason)
+				} else {
+					fmt.Fprintf(cmd.ErrOrStderr(), "clone: %s from %s (%s)\n", result.Path, result.Source, result.Method)
+				}
+				results = append(results, result)
+			}
+			if matched {
+				break
+			}
+		}
+	}
+	return results
+}
+
+// cloneSources lists the repository's other worktrees, most recently visited
+// first; the main checkout leads those never visited.
+func (a *application) cloneSources(repository *catalog.Repository, root string) []string {
+	type candidate struct {
+		path      string
+		visitedAt time.Time
+	}
+	worktrees, err := repository.Git.Worktrees()
+	if err != nil {
+		return []string{repository.Git.MainPath}
+	}
+	candidates := make([]candidate, 0, len(worktrees))
+	for _, worktree := range worktrees {
+		if worktree.Prunable {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(worktree.Path); err != nil || resolved == root {
+			continue
+		}
+		visitedAt, _ := a.dependencies.lastVisited(worktree.Path)
+		if worktree.Main {
+			// Main sorts first among equals, including the never visited.
+			candidates = append([]candidate{{path: worktree.Path, visitedAt: visitedAt}}, candidates...)
+			continue
+		}
+		candidates = append(candidates, candidate{path: worktree.Path, visitedAt: visitedAt})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].visitedAt.After(candidates[j].visitedAt)
+	})
+	paths := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		paths = append(paths, candidate.path)
+	}
+	return paths
+}
+
+func cloneDirectory(root, source, rel string) cloneResult {
+	result := cloneResult{Path: rel, Source: source, Method: "skipped"}
+	destination := filepath.Join(root, filepath.FromSlash(rel))
+	if !pathInside(root, destination) {
+		result.Reason = "escapes the worktree"
+		return result
+	}
+	if err := makeParentInside(root, filepath.Dir(destination)); err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	if _, err := os.Lstat(destination); err == nil {
+		result.Reason = "already exists in the worktree"
+		return result
+	}
+	from := filepath.Join(source, filepath.FromSlash(rel))
+	reflinkErr := cloneTree(from, destination, reflinkFile)
+	if reflinkErr == nil {
+		result.Method = "reflink"
+		return result
+	}
+	os.RemoveAll(destination)
+	hardlink := func(source, destination string, _ os.FileMode) error {
+		return os.Link(source, destination)
+	}
+	if err := cloneTree(from, destination, hardlink); err != nil {
+		os.RemoveAll(destination)
+		result.Reason = fmt.Sprintf("reflink: %v; hard link: %v", reflinkErr, err)
+		return result
+	}
+	result.Method = "hardlink"
+	return result
+}
+
+// cloneTree recreates the directory tree at source under destination, placing
+// each regular file with place and copying symlinks as symlinks.
+func cloneTree(source, destination string, place func(source, destination string, mode os.FileMode) error) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsDir():
+			return os.Mkdir(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return place(path, target, info.Mode().Perm())
+		}
+		// Sockets, FIFOs, and devices have no place in a cache copy.
+		return nil
+	})
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"grove/internal/catalog"
+
+	"github.com/spf13/cobra"
+)
+
+// worktreeFileResult reports one file a profile's copy or link patterns
+// brought from the main checkout into a new worktree. Path is relative to the
+// worktree root, and Action is copied, linked, or skipped.
+type worktreeFileResult struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// copyWorktreeFiles applies the profile's copy and link patterns from the main
+// checkout into a new worktree. Paths that already exist in the worktree,
+// which are its tracked files, are never replaced, and nothing is written
+// outside the worktree. Problems are reported per file rather than failing.
+func copyWorktreeFiles(cmd *cobra.Command, repository *catalog.Repository, profile *catalog.Profile, worktreePath string) []worktreeFileResult {
+	if profile == nil || len(profile.Copy)+len(profile.Link) == 0 {
+		return nil
+	}
+	root, err := filepath.EvalSymlinks(worktreePath)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: copying files skipped: resolving worktree: %v\n", err)
+		return nil
+	}
+	mainPath := repository.Git.MainPath
+	source := os.DirFS(mainPath)
+	results := make([]worktreeFileResult, 0)
+	seen := make(map[string]bool)
+	place := func(rel string, link bool) {
+		if seen[rel] {
+			return
+		}
+		seen[rel] = true
+		result := placeWorktreeFile(root, mainPath, rel, link)
+		switch result.Action {
+		case "skipped":
+			verb := "copying"
+			if link {
+				verb = "linking"
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: not %s %s: %s\n", verb, result.Path, result.Reason)
+		case "linked":
+			fmt.Fprintf(cmd.ErrOrStderr(), "link: %s\n", result.Path)
+		default:
+			fmt.Fprintf(cmd.ErrOrStderr(), "copy: %s\n", result.Path)
+		}
+		results = append(results, result)
+	}
+	apply := func(patterns []string, link bool) {
+		for _, pattern := range patterns {
+			matches, err := fs.Glob(source, filepath.ToSlash(pattern))
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: pattern %q: %v\n", pattern, err)
+				continue
+			}
+			for _, match := range matches {
+				if reservedCheckoutPath(match) {
+					continue
+				}
+				info, err := os.Lstat(filepath.Join(mainPath, filepath.FromSlash(match)))
+				if err != nil || link || !info.IsDir() {
+					place(match, link)
+					continue
+				}
+				// Copying a directory copies the files beneath it one by one, so
+				// tracked files inside it stay untouched.
+				_ = fs.WalkDir(source, match, func(rel string, entry fs.DirEntry, err error) error {
+					if err != nil || reservedCheckoutPath(rel) {
+						if entry != nil && entry.IsDir() {
+							return fs.SkipDir
+						}
+						return nil
+					}
+					if !entry.IsDir() {
+						place(rel, false)
+					}
+					return nil
+				})
+			}
+		}
+	}
+	apply(profile.Copy, false)
+	apply(profile.Link, true)
+	return results
+}
+
+// reservedCheckoutPath reports whether a slash-separated path from the main
+// checkout belongs to Git or to Grove's worktrees.
+func reservedCheckoutPath(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if part == ".git" || part == ".wt" {
+			return true
+		}
+	}
+	return rel == "."
+}
+
+func placeWorktreeFile(root, mainPath, rel string, link bool) worktreeFileResult {
+	result := worktreeFileResult{Path: rel, Action: "skipped"}
+	destination := filepath.Join(root, filepath.FromSlash(rel))
+	if !pathInside(root, destination) {
+		result.Reason = "escapes the worktree"
+		return result
+	}
+	if err := makeParentInside(root, filepath.Dir(destination)); err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	if _, err := os.Lstat(destination); err == nil {
+		result.Reason = "already exists in the worktree"
+		return result
+	}
+	source := filepath.Join(mainPath, filepath.FromSlash(rel))
+	if link {
+		if err := os.Symlink(source, destination); err != nil {
+			result.Reason = err.Error()
+			return result
+		}
+		result.Action = "linked"
+		return result
+	}
+	if err := copyFileOrLink(source, destination); err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	result.Action = "copied"
+	return result
+}
+
+// makeParentInside creates directory beneath root, refusing when an existing
+// component resolves outside root through a symlink.
+func makeParentInside(root, directory string) error {
+	existing := directory
+	for {
+		if _, err := os.Lstat(existing); err == nil || existing == root {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return err
+	}
+	if !pathInside(root, resolved) {
+		rel, _ := filepath.Rel(root, existing)
+		return fmt.Errorf("%s escapes the worktree through a symlink", filepath.ToSlash(rel))
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+	if resolved, err = filepath.EvalSymlinks(directory); err != nil || !pathInside(root, resolved) {
+		return fmt.Errorf("parent directory escapes the worktree")
+	}
+	return nil
+}
+
+// copyFileOrLink copies a regular file with its permissions, or recreates a
+// symlink with the same target. It never replaces an existing destination.
+func copyFileOrLink(source, destination string) error {
+	info, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(source)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, destination)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("not a regular file")
+	}
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	output, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(output, input); err != nil {
+		output.Close()
+		os.Remove(destination)
+		return err
+	}
+	return output.Close()
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"grove/internal/catalog"
+	"grove/internal/config"
+	"grove/internal/inventory"
+
+	"github.com/spf13/cobra"
+)
+
+type doctorOutput struct {
+	Version  int             `json:"version"`
+	Healthy  bool            `json:"healthy"`
+	Problems []doctorProblem `json:"problems"`
+}
+
+type doctorProblem struct {
+	Kind       string `json:"kind"`
+	Repository string `json:"repository,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Message    string `json:"message"`
+}
+
+func (a *application) doctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check configuration and worktrees for problems",
+		Long:  "Check configuration and worktrees for problems.\n\nExits non-zero when any problem is found, so scripts and CI can gate on a healthy setup.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runDoctor(cmd)
+		},
+	}
+}
+
+func (a *application) runDoctor(cmd *cobra.Command) error {
+	directory, err := a.workingDirectory()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	// Doctor collects the warnings other commands print as a side effect, so it
+	// builds the catalog and inventory itself instead of using loadContext.
+	cat, warnings := catalog.Build(cfg, directory)
+	inv, failures := inventory.Build(cat)
+	problems := make([]doctorProblem, 0)
+	for _, warning := range warnings {
+		problems = append(problems, doctorProblem{Kind: "config", Repository: warning.Name, Path: warning.Path, Message: warning.Message})
+	}
+	for _, failure := range failures {
+		problems = append(problems, doctorProblem{Kind: "inventory", Repository: failure.Repository, Message: failure.Err.Error()})
+	}
+	problems = append(problems, diagnoseRepositories(cat, inv)...)
+
+	if a.jsonOutput {
+		if err := writeJSON(cmd, doctorOutput{Version: 1, Healthy: len(problems) == 0, Problems: problems}); err != nil {
+			return err
+		}
+	} else if len(problems) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No problems found.")
+	} else {
+		style := a.style(cmd.OutOrStdout())
+		for _, problem := range problems {
+			subject := problem.Repository
+			if subject == "" {
+				subject = problem.Path
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s  %s\n", style.danger(problem.Kind+":"), style.heading(subject), problem.Message)
+		}
+	}
+	if len(problems) != 0 {
+		return fmt.Errorf("doctor found %s", worktreeCount(len(problems), "problem", "problems"))
+	}
+	return nil
+}
+
+func diagnoseRepositories(cat *catalog.Catalog, inv *inventory.Inventory) []doctorProblem {
+	var problems []doctorProblem
+	byRepository := make(map[*catalog.Repository][]*inventory.Entry)
+	for _, entry := range inv.Entries {
+		byRepository[entry.Repository] = append(byRepository[entry.Repository], entry)
+	}
+	for _, repository := range cat.Repositories {
+		tracked, err := repository.Git.TrackedManagedFiles()
+		if err != nil {
+			problems = append(problems, doctorProblem{Kind: "tracked", Repository: repository.Name, Path: repository.Git.MainPath, Message: err.Error()})
+		} else if len(tracked) != 0 {
+			problems = append(problems, doctorProblem{
+				Kind:       "tracked",
+				Repository: repository.Name,
+				Path:       filepath.Join(repository.Git.MainPath, ".wt"),
+				Message:    fmt.Sprintf(".wt contains %s (%s); grove refuses to create worktrees there", worktreeCount(len(tracked), "tracked file", "tracked files"), strings.Join(firstStrings(tracked, 3), ", ")),
+			})
+		}
+		if _, err := os.Lstat(filepath.Join(repository.Git.MainPath, ".wt")); err == nil {
+			excluded, err := repository.Git.ManagedRootExcluded()
+			if err != nil {
+				problems = append(problems, doctorProblem{Kind: "exclude", Repository: repository.Name, Path: repository.Git.MainPath, Message: err.Error()})
+			} else if !excluded {
+				problems = append(problems, doctorProblem{Kind: "exclude", Repository: repository.Name, Path: repository.Git.MainPath, Message: "/.wt/ is missing from .git/info/exclude; the main checkout shows worktrees as untracked"})
+			}
+		}
+		for _, entry := range byRepository[repository] {
+			switch {
+			case entry.Worktree.Prunable:
+				problems = append(problems, doctorProblem{Kind: "prunable", Repository: repository.Name, Path: entry.Worktree.Path, Message: entry.Selector() + " is registered but its directory is missing; run grove rm --missing"})
+			case entry.Worktree.Main:
+			case !repository.Git.WorktreeGitFileValid(entry.Worktree.Path):
+				problems = append(problems, doctorProblem{Kind: "git_pointer", Repository: repository.Name, Path: entry.Worktree.Path, Message: entry.Selector() + " has a .git file that does not point at its registration; run git worktree repair"})
+			}
+		}
+	}
+	return problems
+}
+
+func firstStrings(values []string, limit int) []string {
+	if len(values) <= limit {
+		return values
+	}
+	return append(append([]string{}, values[:limit]...), "…")
+}
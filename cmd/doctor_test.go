@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDoctorReportsHealthySetup(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "new", "auth"); err != nil {
+		t.Fatalf("new error = %v", err)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "doctor")
+	if err != nil {
+		t.Fatalf("doctor error = %v\n%s", err, stdout)
+	}
+	if stdout != "No problems found.\n" {
+		t.Fatalf("stdout = %q", stdout)
+	}
+}
+
+func TestDoctorReportsEveryProblemAndFails(t *testing.T) {
+	repoPath := initV2Repo(t)
+	missingConfigPath := filepath.Join(t.TempDir(), "deleted")
+	writeV2Config(t, repoPath, "  - path: "+missingConfigPath+"\n    name: gone\n")
+	for _, branch := range []string{"first", "second", "missing"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		if _, _, err := executeV2(root, "new", branch); err != nil {
+			t.Fatalf("new %s error = %v", branch, err)
+		}
+	}
+	managedRoot := filepath.Join(canonicalV2Path(t, repoPath), ".wt", "feat")
+	secondGitFile, err := os.ReadFile(filepath.Join(managedRoot, "second", ".git"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(managedRoot, "first", ".git"), secondGitFile, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(managedRoot, "missing")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoPath, ".git", "info", "exclude"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "doctor")
+	if err == nil || !strings.Contains(err.Error(), "4 problems") {
+		t.Fatalf("doctor error = %v, want 4 problems\n%s", err, stdout)
+	}
+	var output doctorOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	kinds := make(map[string]string)
+	for _, problem := range output.Problems {
+		kinds[problem.Kind] = problem.Path
+	}
+	if output.Healthy || kinds["config"] != missingConfigPath || kinds["prunable"] == "" || kinds["exclude"] == "" || kinds["git_pointer"] != filepath.Join(managedRoot, "first") {
+		t.Fatalf("doctor output = %#v", output)
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"grove/internal/catalog"
+	"grove/internal/inventory"
+
+	"github.com/spf13/cobra"
+)
+
+const fetchTimeout = 60 * time.Second
+
+// fetchRepositories fetches every repository that asked for it, either through
+// --fetch or its fetch config field, all at once under one timeout. A failed
+// fetch is only a warning: the command continues with the refs it already has.
+// It reports which repositories were fetched successfully.
+func fetchRepositories(cmd *cobra.Command, repositories []*catalog.Repository, requested bool) map[*catalog.Repository]bool {
+	var targets []*catalog.Repository
+	seen := make(map[*catalog.Repository]bool)
+	for _, repository := range repositories {
+		if repository == nil || seen[repository] || !(requested || repository.Fetch) {
+			continue
+		}
+		seen[repository] = true
+		targets = append(targets, repository)
+	}
+	fetched := make(map[*catalog.Repository]bool, len(targets))
+	if len(targets) == 0 {
+		return fetched
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for index, repository := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[index] = repository.Git.Fetch(ctx, repository.Git.Remote())
+		}()
+	}
+	wg.Wait()
+	for index, repository := range targets {
+		if errs[index] != nil {
+			failure := inventory.Failure{Repository: repository.Name, Err: errs[index]}
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", failure.Error())
+			continue
+		}
+		fetched[repository] = true
+	}
+	return fetched
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"grove/internal/catalog"
+	"grove/internal/config"
+	"grove/internal/inventory"
+
+	"github.com/spf13/cobra"
+)
+
+// Hook names, as written under hooks: in the config and reported in --json.
+const (
+	hookPostCreate = "post_create"
+	hookPreRemove  = "pre_remove"
+	hookPostRemove = "post_remove"
+	hookPostVisit  = "post_visit"
+)
+
+// hookSteps selects one hook's steps from a profile's hooks.
+func hookSteps(hooks config.Hooks, name string) []config.SetupStep {
+	switch name {
+	case hookPostCreate:
+		return hooks.PostCreate
+	case hookPreRemove:
+		return hooks.PreRemove
+	case hookPostRemove:
+		return hooks.PostRemove
+	default:
+		return hooks.PostVisit
+	}
+}
+
+// runHook runs one hook for each target in turn and stops at the first
+// failure, which it returns.
+func runHook(cmd *cobra.Command, name string, targets []setupTarget) ([]setupStepResult, error) {
+	var results []setupStepResult
+	for _, target := range targets {
+		if target.profile == nil {
+			continue
+		}
+		stepResults, err := runSteps(cmd, target, name, hookSteps(target.profile.Hooks, name))
+		for index := range stepResults {
+			stepResults[index].Hook = name
+		}
+		results = append(results, stepResults...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// entryHookTargets covers every profile of the entry's repository, since a
+// worktree does not record which profile created it. Steps run under root,
+// the worktree itself unless it is gone.
+func entryHookTargets(entry *inventory.Entry, root string) []setupTarget {
+	return repositoryHookTargets(entry.Repository, entry.Worktree.Branch, entry.Worktree.Path, root)
+}
+
+// repositoryHookTargets leaves out profiles whose workdir does not exist under
+// root: such a worktree was not created for them, and their hooks failing
+// there must not veto a removal.
+func repositoryHookTargets(repository *catalog.Repository, branch, path, root string) []setupTarget {
+	directory := root
+	if directory == "" {
+		directory = path
+	}
+	targets := make([]setupTarget, 0, len(repository.Profiles))
+	for _, profile := range repository.Profiles {
+		if profile.Workdir != "" && !filepath.IsAbs(profile.Workdir) {
+			if _, err := os.Stat(filepath.Join(directory, profile.Workdir)); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+		targets = append(targets, setupTarget{repository: repository, profile: profile, branch: branch, path: path, root: root})
+	}
+	return targets
+}
+
+// runPreRemoveHooks runs pre_remove for every entry before any is removed, so
+// one veto aborts the whole removal. Results are keyed by worktree path. A
+// worktree already missing from disk runs it from the main checkout, as
+// post_remove does.
+func runPreRemoveHooks(cmd *cobra.Command, entries []*inventory.Entry) (map[string][]setupStepResult, error) {
+	results := make(map[string][]setupStepResult, len(entries))
+	for _, entry := range entries {
+		root := ""
+		if entry.Worktree.Prunable {
+			root = entry.Repository.Git.MainPath
+		}
+		hookResults, err := runHook(cmd, hookPreRemove, entryHookTargets(entry, root))
+		if err != nil {
+			return nil, fmt.Errorf("%s: removal vetoed by %w", entry.Selector(), err)
+		}
+		results[entry.Worktree.Path] = hookResults
+	}
+	return results, nil
+}
+
+// runPostRemoveHook runs post_remove from the main checkout, the worktree
+// being gone, and only warns when it fails.
+func runPostRemoveHook(cmd *cobra.Command, entry *inventory.Entry) []setupStepResult {
+	results, err := runHook(cmd, hookPostRemove, entryHookTargets(entry, entry.Repository.Git.MainPath))
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s: %v\n", entry.Selector(), err)
+	}
+	return results
+}
+
+// runPostCreateHook runs post_create for the profile that created a worktree,
+// after its setup, and only warns when it fails.
+func runPostCreateHook(cmd *cobra.Command, target setupTarget) []setupStepResult {
+	results, err := runHook(cmd, hookPostCreate, []setupTarget{target})
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+	}
+	return results
+}
+
+// runPostVisitHook runs post_visit once a visit is recorded; its output goes
+// to stderr so the printed path stays the only thing on stdout.
+func runPostVisitHook(cmd *cobra.Command, entry *inventory.Entry) {
+	if _, err := runHook(cmd, hookPostVisit, entryHookTargets(entry, "")); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+	}
+}
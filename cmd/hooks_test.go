@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHooksRunAroundCreateVisitAndRemove(t *testing.T) {
+	repoPath := initV2Repo(t)
+	state := t.TempDir()
+	t.Setenv("HOOK_STATE", state)
+	writeV2Config(t, "", strings.Join([]string{
+		"  - path: " + repoPath,
+		"    name: app",
+		"    default_branch: main",
+		"    hooks:",
+		"      post_create: ['echo \"create $GROVE_BRANCH\" >> \"$HOOK_STATE/log\"']",
+		"      post_visit: ['echo \"visit $GROVE_BRANCH\" >> \"$HOOK_STATE/log\"']",
+		"      pre_remove:",
+		"        - run: test ! -e \"$HOOK_STATE/veto-$(basename \"$GROVE_WORKTREE\")\"",
+		"      post_remove: ['echo \"remove $GROVE_BRANCH $(pwd)\" >> \"$HOOK_STATE/log\"']",
+		"",
+	}, "\n"))
+	newWorktree := func(branch string) newOutput {
+		t.Helper()
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		stdout, stderr, err := executeV2(root, "--json", "new", branch)
+		if err != nil {
+			t.Fatalf("new %s error = %v; stderr=%s", branch, err, stderr)
+		}
+		var output newOutput
+		if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+			t.Fatalf("invalid JSON %q: %v", stdout, err)
+		}
+		return output
+	}
+	first := newWorktree("one")
+	if len(first.Hooks) != 1 || first.Hooks[0].Hook != hookPostCreate || first.Hooks[0].Status != setupOK {
+		t.Fatalf("post_create results = %#v", first.Hooks)
+	}
+	newWorktree("two")
+	root := newRootCommand(commandDependencies{
+		getwd:       func() (string, error) { return repoPath, nil },
+		markVisited: func(string) error { return nil },
+	})
+	if stdout, _, err := executeV2(root, "cd", "feat/one"); err != nil || strings.TrimSpace(stdout) != first.Path {
+		t.Fatalf("cd = %q, %v", stdout, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(state, "veto-two"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "feat/one", "feat/two"); err == nil || !strings.Contains(err.Error(), "feat/two: removal vetoed by pre_remove") {
+		t.Fatalf("vetoed rm error = %v", err)
+	}
+	if _, err := os.Stat(first.Path); err != nil {
+		t.Fatalf("veto of one target still removed another: %v", err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "--merged"); err == nil || !strings.Contains(err.Error(), "vetoed") {
+		t.Fatalf("vetoed rm --merged error = %v", err)
+	}
+	if _, err := os.Stat(first.Path); err != nil {
+		t.Fatalf("bulk veto still removed a worktree: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(state, "veto-two")); err != nil {
+		t.Fatal(err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "rm", "--merged")
+	if err != nil {
+		t.Fatalf("rm --merged error = %v", err)
+	}
+	var output removeOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.Removed) != 2 {
+		t.Fatalf("removed = %#v", output.Removed)
+	}
+	for _, result := range output.Removed {
+		if len(result.Hooks) != 2 || result.Hooks[0].Hook != hookPreRemove || result.Hooks[1].Hook != hookPostRemove {
+			t.Fatalf("%s hooks = %#v", result.Selector, result.Hooks)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(state, "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainPath := canonicalV2Path(t, repoPath)
+	want := "create feat/one\ncreate feat/two\nvisit feat/one\nremove feat/one " + mainPath + "\nremove feat/two " + mainPath + "\n"
+	if string(data) != want {
+		t.Fatalf("hook log = %q, want %q", data, want)
+	}
+
+	// --missing runs pre_remove from the main checkout before pruning, so it
+	// can still veto.
+	third := newWorktree("three")
+	if err := os.RemoveAll(third.Path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(state, "veto-three"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "--missing"); err == nil || !strings.Contains(err.Error(), "feat/three: removal vetoed by pre_remove") {
+		t.Fatalf("vetoed rm --missing error = %v", err)
+	}
+	if listing := v2GitOutput(t, repoPath, "worktree", "list", "--porcelain"); !strings.Contains(listing, third.Path) {
+		t.Fatalf("vetoed rm --missing pruned the registration:\n%s", listing)
+	}
+	if err := os.Remove(filepath.Join(state, "veto-three")); err != nil {
+		t.Fatal(err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--json", "rm", "--missing")
+	if err != nil {
+		t.Fatalf("rm --missing error = %v", err)
+	}
+	output = removeOutput{}
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.Removed) != 1 || len(output.Removed[0].Hooks) != 2 || output.Removed[0].Hooks[0].Hook != hookPreRemove {
+		t.Fatalf("rm --missing = %#v", output.Removed)
+	}
+}
+
+func TestRemoveHooksSkipProfilesWhoseWorkdirIsMissing(t *testing.T) {
+	repoPath := initV2Repo(t)
+	state := t.TempDir()
+	t.Setenv("HOOK_STATE", state)
+	writeV2Config(t, "", strings.Join([]string{
+		"  - path: " + repoPath,
+		"    name: agent",
+		"    default_branch: main",
+		"    workdir: packages/agent",
+		"    hooks:",
+		"      pre_remove: ['echo \"agent $GROVE_BRANCH\" >> \"$HOOK_STATE/log\"']",
+		"  - path: " + repoPath,
+		"    name: app",
+		"    default_branch: main",
+		"    hooks:",
+		"      pre_remove: ['echo \"app $GROVE_BRANCH\" >> \"$HOOK_STATE/log\"']",
+		"",
+	}, "\n"))
+	base := t.TempDir()
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/plain", filepath.Join(base, "plain"))
+	agentPath := filepath.Join(base, "agent")
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/agent", agentPath)
+	if err := os.MkdirAll(filepath.Join(agentPath, "packages", "agent"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, branch := range []string{"feat/plain", "feat/agent"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		if _, stderr, err := executeV2(root, "rm", branch); err != nil {
+			t.Fatalf("rm %s error = %v; stderr=%s", branch, err, stderr)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(state, "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "app feat/plain\nagent feat/agent\napp feat/agent\n"; string(data) != want {
+		t.Fatalf("hook log = %q, want %q", data, want)
+	}
+}
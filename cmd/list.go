@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"grove/internal/catalog"
@@ -28,40 +29,57 @@ type listRepository struct {
 }
 
 type listWorktree struct {
-	Branch      string `json:"branch,omitempty"`
-	Head        string `json:"head"`
-	Path        string `json:"path"`
-	Main        bool   `json:"main"`
-	Detached    bool   `json:"detached"`
-	Locked      bool   `json:"locked"`
-	LockReason  string `json:"lock_reason,omitempty"`
-	Prunable    bool   `json:"prunable"`
-	Dirty       *bool  `json:"dirty,omitempty"`
-	Ahead       *int   `json:"ahead,omitempty"`
-	Behind      *int   `json:"behind,omitempty"`
-	StatusError string `json:"status_error,omitempty"`
+	Branch         string `json:"branch,omitempty"`
+	Head           string `json:"head"`
+	Path           string `json:"path"`
+	Main           bool   `json:"main"`
+	Detached       bool   `json:"detached"`
+	Parent         string `json:"parent,omitempty"`
+	Locked         bool   `json:"locked"`
+	LockReason     string `json:"lock_reason,omitempty"`
+	Prunable       bool   `json:"prunable"`
+	Dirty          *bool  `json:"dirty,omitempty"`
+	Staged         *int   `json:"staged,omitempty"`
+	Modified       *int   `json:"modified,omitempty"`
+	Untracked      *int   `json:"untracked,omitempty"`
+	Conflicted     *int   `json:"conflicted,omitempty"`
+	Operation      string `json:"operation,omitempty"`
+	Ahead          *int   `json:"ahead,omitempty"`
+	Behind         *int   `json:"behind,omitempty"`
+	Upstream       string `json:"upstream,omitempty"`
+	UpstreamGone   bool   `json:"upstream_gone,omitempty"`
+	UpstreamAhead  *int   `json:"upstream_ahead,omitempty"`
+	UpstreamBehind *int   `json:"upstream_behind,omitempty"`
+	StatusError    string `json:"status_error,omitempty"`
 }
 
+const defaultStatusJobs = 8
+
 func (a *application) listCommand() *cobra.Command {
 	var status bool
+	var jobs int
 	command := &cobra.Command{
 		Use:   "list",
 		Short: "List repositories and worktrees",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return a.runList(cmd, status)
+			if jobs < 1 {
+				return fmt.Errorf("--jobs must be at least 1")
+			}
+			return a.runList(cmd, status, jobs)
 		},
 	}
-	command.Flags().BoolVar(&status, "status", false, "Check dirty and ahead/behind status")
+	command.Flags().BoolVar(&status, "status", false, "Check file, operation, and ahead/behind status")
+	command.Flags().IntVar(&jobs, "jobs", defaultStatusJobs, "Number of worktrees to check status for concurrently")
 	return command
 }
 
-func (a *application) runList(cmd *cobra.Command, includeStatus bool) error {
+func (a *application) runList(cmd *cobra.Command, includeStatus bool, jobs int) error {
 	context, err := a.loadContext(cmd)
 	if err != nil {
 		return err
 	}
-	document := buildListDocument(context.catalog, context.inventory, includeStatus)
+	document := buildListDocument(context.catalog, context.inventory, includeStatus, jobs)
 	if a.jsonOutput {
 		return writeJSON(cmd, document)
 	}
@@ -89,12 +107,12 @@ func (a *application) runList(cmd *cobra.Command, includeStatus bool) error {
 		shownRepositories++
 		aliases := aliasesSuffix(repository.Name, repository.Aliases)
 		fmt.Fprintf(cmd.OutOrStdout(), "%s%s  %s\n", style.heading(repository.Name), style.muted(aliases), style.muted(repository.Path))
-		for worktreeIndex, worktree := range worktrees {
+		for _, row := range stackRows(worktrees) {
 			connector := "├──"
-			if worktreeIndex == len(worktrees)-1 {
+			if row.last {
 				connector = "└──"
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "%s %s%s%s\n", style.muted(connector), styledWorktreeLabel(style, worktree), styledStatusSuffix(style, worktree), style.muted(createdSuffix(worktree, now)))
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s%s%s\n", style.muted(row.prefix+connector), styledWorktreeLabel(style, row.worktree), styledStatusSuffix(style, row.worktree), style.muted(createdSuffix(row.worktree, now)))
 		}
 	}
 	if shownRepositories == 0 {
@@ -126,6 +144,78 @@ func sortWorktreesNewestFirst(worktrees []listWorktree) {
 	})
 }
 
+type stackRow struct {
+	worktree listWorktree
+	prefix   string
+	last     bool
+}
+
+// stackRows nests stacked branches under the worktree of their recorded parent
+// while keeping the caller's order among siblings. A worktree whose parent is
+// not shown, or that sits in a parent cycle, is rendered at the top level.
+func stackRows(worktrees []listWorktree) []stackRow {
+	byBranch := make(map[string]int, len(worktrees))
+	for index, worktree := range worktrees {
+		if worktree.Branch != "" {
+			byBranch[worktree.Branch] = index
+		}
+	}
+	children := make(map[int][]int)
+	var roots []int
+	for index, worktree := range worktrees {
+		parent, ok := byBranch[worktree.Parent]
+		if worktree.Parent == "" || !ok || parent == index {
+			roots = append(roots, index)
+			continue
+		}
+		children[parent] = append(children[parent], index)
+	}
+	reached := make(map[int]bool, len(worktrees))
+	var reach func(int)
+	reach = func(index int) {
+		if reached[index] {
+			return
+		}
+		reached[index] = true
+		for _, child := range children[index] {
+			reach(child)
+		}
+	}
+	for _, root := range roots {
+		reach(root)
+	}
+	for index := range worktrees {
+		if !reached[index] {
+			roots = append(roots, index)
+			reach(index)
+		}
+	}
+
+	rows := make([]stackRow, 0, len(worktrees))
+	visited := make(map[int]bool, len(worktrees))
+	var visit func(indexes []int, prefix string)
+	visit = func(indexes []int, prefix string) {
+		pending := make([]int, 0, len(indexes))
+		for _, index := range indexes {
+			if !visited[index] {
+				visited[index] = true
+				pending = append(pending, index)
+			}
+		}
+		for position, index := range pending {
+			last := position == len(pending)-1
+			rows = append(rows, stackRow{worktree: worktrees[index], prefix: prefix, last: last})
+			nested := prefix + "│   "
+			if last {
+				nested = prefix + "    "
+			}
+			visit(children[index], nested)
+		}
+	}
+	visit(roots, "")
+	return rows
+}
+
 func styledWorktreeLabel(style outputStyle, worktree listWorktree) string {
 	label := worktree.Branch
 	if label == "" {
@@ -149,8 +239,21 @@ func styledStatusSuffix(style outputStyle, worktree listWorktree) string {
 		return ""
 	}
 	var labels []string
-	if worktree.Dirty != nil && *worktree.Dirty {
-		labels = append(labels, style.attention("dirty"))
+	if worktree.Operation != "" {
+		labels = append(labels, style.danger(worktree.Operation+" in progress"))
+	}
+	for _, count := range []struct {
+		value *int
+		label string
+	}{
+		{value: worktree.Conflicted, label: "conflicted"},
+		{value: worktree.Staged, label: "staged"},
+		{value: worktree.Modified, label: "modified"},
+		{value: worktree.Untracked, label: "untracked"},
+	} {
+		if count.value != nil && *count.value > 0 {
+			labels = append(labels, style.attention(fmt.Sprintf("%d %s", *count.value, count.label)))
+		}
 	}
 	if worktree.Ahead != nil && *worktree.Ahead > 0 {
 		labels = append(labels, style.info(fmt.Sprintf("↑%d", *worktree.Ahead)))
@@ -158,6 +261,15 @@ func styledStatusSuffix(style outputStyle, worktree listWorktree) string {
 	if worktree.Behind != nil && *worktree.Behind > 0 {
 		labels = append(labels, style.info(fmt.Sprintf("↓%d", *worktree.Behind)))
 	}
+	if worktree.UpstreamGone {
+		labels = append(labels, style.danger("upstream gone"))
+	}
+	if worktree.UpstreamAhead != nil && *worktree.UpstreamAhead > 0 {
+		labels = append(labels, style.info(fmt.Sprintf("⇡%d", *worktree.UpstreamAhead)))
+	}
+	if worktree.UpstreamBehind != nil && *worktree.UpstreamBehind > 0 {
+		labels = append(labels, style.info(fmt.Sprintf("⇣%d", *worktree.UpstreamBehind)))
+	}
 	if worktree.StatusError != "" {
 		labels = append(labels, style.danger("status unavailable"))
 	}
@@ -214,7 +326,14 @@ func relativeAge(age time.Duration) string {
 	}
 }
 
-func buildListDocument(cat *catalog.Catalog, inv *inventory.Inventory, includeStatus bool) listDocument {
+type statusJob struct {
+	repository *catalog.Repository
+	base       string
+	baseErr    error
+	worktree   *listWorktree
+}
+
+func buildListDocument(cat *catalog.Catalog, inv *inventory.Inventory, includeStatus bool, jobs int) listDocument {
 	document := listDocument{Version: 1, Repositories: make([]listRepository, 0, len(cat.Repositories))}
 	byRepository := make(map[*catalog.Repository][]*inventory.Entry)
 	for _, entry := range inv.Entries {
@@ -228,8 +347,12 @@ func buildListDocument(cat *catalog.Catalog, inv *inventory.Inventory, includeSt
 			DefaultBranch: repository.DefaultBranch,
 			Worktrees:     []listWorktree{},
 		}
+		// Stack parents are optional presentation metadata; an unreadable config
+		// leaves the list flat rather than failing it.
+		parents, _ := repository.Git.BranchParents()
 		for _, entry := range byRepository[repository] {
-			worktree := listWorktree{
+			item.Worktrees = append(item.Worktrees, listWorktree{
+				Parent:     parents[entry.Worktree.Branch],
 				Branch:     entry.Worktree.Branch,
 				Head:       entry.Worktree.Head,
 				Path:       entry.Worktree.Path,
@@ -238,29 +361,94 @@ func buildListDocument(cat *catalog.Catalog, inv *inventory.Inventory, includeSt
 				Locked:     entry.Worktree.Locked,
 				LockReason: entry.Worktree.LockReason,
 				Prunable:   entry.Worktree.Prunable,
-			}
-			if includeStatus && !worktree.Prunable {
-				dirty, err := repository.Git.Dirty(worktree.Path)
-				if err != nil {
-					worktree.StatusError = err.Error()
-				} else {
-					worktree.Dirty = &dirty
-					ahead, behind, err := repository.Git.AheadBehind(worktree.Path, repository.DefaultBranch)
-					if err != nil {
-						worktree.StatusError = err.Error()
-					} else {
-						worktree.Ahead = &ahead
-						worktree.Behind = &behind
-					}
-				}
-			}
-			item.Worktrees = append(item.Worktrees, worktree)
+			})
 		}
 		document.Repositories = append(document.Repositories, item)
 	}
+	if includeStatus {
+		collectListStatus(cat, document, jobs)
+	}
 	return document
 }
 
+// collectListStatus fills status fields in place. Every job owns one worktree
+// slot in the already ordered document, so workers finish in any order without
+// changing the output, and each repository resolves its base ref only once.
+func collectListStatus(cat *catalog.Catalog, document listDocument, jobs int) {
+	var pending []statusJob
+	for index, repository := range cat.Repositories {
+		worktrees := document.Repositories[index].Worktrees
+		resolved := false
+		var base string
+		var baseErr error
+		for worktreeIndex := range worktrees {
+			if worktrees[worktreeIndex].Prunable {
+				continue
+			}
+			if !resolved {
+				base, baseErr = repository.Git.BaseRef(repository.DefaultBranch)
+				resolved = true
+			}
+			pending = append(pending, statusJob{repository: repository, base: base, baseErr: baseErr, worktree: &worktrees[worktreeIndex]})
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+	if jobs > len(pending) {
+		jobs = len(pending)
+	}
+	queue := make(chan statusJob)
+	var workers sync.WaitGroup
+	for range jobs {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range queue {
+				job.run()
+			}
+		}()
+	}
+	for _, job := range pending {
+		queue <- job
+	}
+	close(queue)
+	workers.Wait()
+}
+
+func (j statusJob) run() {
+	worktree := j.worktree
+	status, err := j.repository.Git.Status(worktree.Path)
+	if err != nil {
+		worktree.StatusError = err.Error()
+		return
+	}
+	dirty := status.Dirty()
+	worktree.Dirty = &dirty
+	worktree.Staged = &status.Staged
+	worktree.Modified = &status.Modified
+	worktree.Untracked = &status.Untracked
+	worktree.Conflicted = &status.Conflicted
+	worktree.Operation = status.Operation
+	worktree.Upstream = status.Upstream
+	worktree.UpstreamGone = status.UpstreamGone
+	if status.Upstream != "" && !status.UpstreamGone {
+		worktree.UpstreamAhead = &status.UpstreamAhead
+		worktree.UpstreamBehind = &status.UpstreamBehind
+	}
+	if j.baseErr != nil {
+		worktree.StatusError = j.baseErr.Error()
+		return
+	}
+	ahead, behind, err := j.repository.Git.AheadBehindRef(worktree.Path, j.base)
+	if err != nil {
+		worktree.StatusError = err.Error()
+		return
+	}
+	worktree.Ahead = &ahead
+	worktree.Behind = &behind
+}
+
 func aliasesSuffix(name string, aliases []string) string {
 	others := make([]string, 0, len(aliases))
 	for _, alias := range aliases {
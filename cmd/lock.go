@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"grove/internal/inventory"
+	"grove/internal/picker"
+
+	"github.com/spf13/cobra"
+)
+
+type lockOutput struct {
+	Version   int          `json:"version"`
+	Worktrees []lockResult `json:"worktrees"`
+}
+
+type lockResult struct {
+	Selector   string `json:"selector"`
+	Path       string `json:"path"`
+	Locked     bool   `json:"locked"`
+	LockReason string `json:"lock_reason,omitempty"`
+	// Changed is false when the worktree was already in the requested state.
+	Changed bool `json:"changed"`
+}
+
+func (a *application) lockCommand() *cobra.Command {
+	var reason string
+	command := &cobra.Command{
+		Use:   "lock [selector...]",
+		Short: "Lock worktrees so removal and cleanup leave them alone",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.ContainsAny(reason, "\r\n") {
+				return fmt.Errorf("--reason must be a single line")
+			}
+			return a.runLock(cmd, args, true, strings.TrimSpace(reason))
+		},
+	}
+	command.Flags().StringVar(&reason, "reason", "", "Explain why the worktree is locked; shown when removal refuses it")
+	return command
+}
+
+func (a *application) unlockCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlock [selector...]",
+		Short: "Unlock worktrees",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runLock(cmd, args, false, "")
+		},
+	}
+}
+
+func (a *application) runLock(cmd *cobra.Command, args []string, lock bool, reason string) error {
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	var entries []*inventory.Entry
+	if len(args) != 0 {
+		for _, selector := range args {
+			entry, resolveErr := a.resolveSelector(context, selector, false)
+			if resolveErr != nil {
+				return resolveErr
+			}
+			entries = appendUniqueEntry(entries, entry)
+		}
+	} else if lock {
+		entries, err = a.pickLinkedWorktrees(context, "lock > ", func(entry *inventory.Entry) bool {
+			return !entry.Worktree.Locked
+		})
+	} else {
+		entries, err = a.pickLinkedWorktrees(context, "unlock > ", func(entry *inventory.Entry) bool {
+			return entry.Worktree.Locked
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return picker.ErrCancelled
+	}
+	for _, entry := range entries {
+		if entry.Worktree.Main {
+			return fmt.Errorf("%s: the main worktree cannot be locked or unlocked", entry.Selector())
+		}
+	}
+
+	results := make([]lockResult, 0, len(entries))
+	for _, entry := range entries {
+		result, err := setWorktreeLock(entry, lock, reason)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Selector(), err)
+		}
+		results = append(results, result)
+	}
+	if a.jsonOutput {
+		return writeJSON(cmd, lockOutput{Version: 1, Worktrees: results})
+	}
+	style := a.style(cmd.OutOrStdout())
+	for _, result := range results {
+		action, state := "Unlocked", "unlocked"
+		if result.Locked {
+			action, state = "Locked", "locked"
+		}
+		detail := ""
+		if result.LockReason != "" {
+			detail = ": " + result.LockReason
+		}
+		if result.Changed {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s%s\n", style.info(action), style.branch(result.Selector), style.muted(detail))
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", style.branch(result.Selector), style.muted("already "+state+detail))
+		}
+	}
+	return nil
+}
+
+// setWorktreeLock moves one worktree into the requested lock state. Locking
+// an already locked worktree with a different reason replaces the reason.
+func setWorktreeLock(entry *inventory.Entry, lock bool, reason string) (lockResult, error) {
+	worktree := entry.Worktree
+	result := lockResult{Selector: entry.Selector(), Path: worktree.Path, Locked: lock}
+	git := entry.Repository.Git
+	if !lock {
+		if worktree.Locked {
+			if err := git.UnlockWorktree(worktree.Path); err != nil {
+				return result, err
+			}
+			result.Changed = true
+		}
+		return result, nil
+	}
+	if worktree.Locked {
+		if reason == "" || reason == strings.TrimSpace(worktree.LockReason) {
+			result.LockReason = strings.TrimSpace(worktree.LockReason)
+			return result, nil
+		}
+		if err := git.UnlockWorktree(worktree.Path); err != nil {
+			return result, err
+		}
+	}
+	if err := git.LockWorktree(worktree.Path, reason); err != nil {
+		if worktree.Locked {
+			// Never leave a worktree that was locked unlocked: put the old
+			// lock back before reporting the failure.
+			if relockErr := git.LockWorktree(worktree.Path, strings.TrimSpace(worktree.LockReason)); relockErr != nil {
+				return result, errors.Join(err, fmt.Errorf("restoring the previous lock: %w", relockErr))
+			}
+		}
+		return result, err
+	}
+	result.LockReason = reason
+	result.Changed = true
+	return result, nil
+}
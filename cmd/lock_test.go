@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLockProtectsWorktreesUntilUnlocked(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	for _, branch := range []string{"agent", "other"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		if _, _, err := executeV2(root, "new", branch); err != nil {
+			t.Fatalf("new %s error = %v", branch, err)
+		}
+	}
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "lock", "--reason", "agent run 42", "feat/agent", "feat/other")
+	if err != nil {
+		t.Fatalf("lock error = %v", err)
+	}
+	var output lockOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.Worktrees) != 2 || !output.Worktrees[0].Locked || !output.Worktrees[0].Changed || output.Worktrees[1].LockReason != "agent run 42" {
+		t.Fatalf("locked = %#v", output.Worktrees)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "lock", "feat/agent")
+	if err != nil || !strings.Contains(stdout, "already locked: agent run 42") {
+		t.Fatalf("lock again = %v, stdout %q", err, stdout)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "--discard", "feat/agent"); err == nil || !strings.Contains(err.Error(), "worktree is locked: agent run 42") {
+		t.Fatalf("rm locked worktree error = %v", err)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "unlock", "feat/agent")
+	if err != nil || !strings.Contains(stdout, "Unlocked app:feat/agent") {
+		t.Fatalf("unlock = %v, stdout %q", err, stdout)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "feat/agent"); err != nil {
+		t.Fatalf("rm unlocked worktree error = %v", err)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "lock", "app:"); err == nil {
+		t.Fatal("lock accepted the main worktree")
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+
+	"grove/internal/inventory"
+	"grove/internal/picker"
+
+	"github.com/spf13/cobra"
+)
+
+type moveOutput struct {
+	Version        int    `json:"version"`
+	Repository     string `json:"repository"`
+	Branch         string `json:"branch"`
+	PreviousBranch string `json:"previous_branch"`
+	Path           string `json:"path"`
+	PreviousPath   string `json:"previous_path"`
+	// Moved is false when the worktree already sat at its managed path.
+	Moved bool `json:"moved"`
+}
+
+func (a *application) moveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mv <selector> <new-branch>",
+		Short: "Rename a worktree's branch and move the worktree to match",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runMove(cmd, args[0], args[1])
+		},
+	}
+}
+
+type adoptOutput struct {
+	Version    int          `json:"version"`
+	DryRun     bool         `json:"dry_run"`
+	Adopted    []moveOutput `json:"adopted"`
+	WouldAdopt []moveOutput `json:"would_adopt"`
+}
+
+func (a *application) adoptCommand() *cobra.Command {
+	var dryRun bool
+	command := &cobra.Command{
+		Use:   "adopt [selector|path...]",
+		Short: "Move worktrees created outside Grove into the managed .wt layout",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runAdopt(cmd, args, dryRun)
+		},
+	}
+	command.Flags().BoolVar(&dryRun, "dry-run", false, "Show where each worktree would move without moving it")
+	return command
+}
+
+// runMove renames the selected worktree's branch to newBranch and moves the
+// worktree to the new branch's managed path.
+func (a *application) runMove(cmd *cobra.Command, selector, newBranch string) error {
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	entry, err := a.resolveSelector(context, selector, false)
+	if err != nil {
+		return err
+	}
+	output, err := a.moveWorktree(cmd, entry, newBranch)
+	if err != nil {
+		return fmt.Errorf("%s: %w", entry.Selector(), err)
+	}
+	if a.jsonOutput {
+		return writeJSON(cmd, output)
+	}
+	return a.writePath(cmd, output.Path)
+}
+
+func (a *application) runAdopt(cmd *cobra.Command, args []string, dryRun bool) error {
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	var entries []*inventory.Entry
+	if len(args) != 0 {
+		for _, selector := range args {
+			entry, resolveErr := a.resolveSelector(context, selector, false)
+			if resolveErr != nil {
+				return resolveErr
+			}
+			entries = appendUniqueEntry(entries, entry)
+		}
+	} else {
+		entries, err = a.pickLinkedWorktrees(context, "adopt > ", outsideManagedLayout)
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return picker.ErrCancelled
+	}
+	if dryRun && a.nullOutput {
+		return fmt.Errorf("--null cannot be used with --dry-run")
+	}
+	// The shell wrapper follows the worktree it was started in when it moves.
+	current := ""
+	if entry, err := context.inventory.Resolve(".", context.directory); err == nil {
+		current = entry.Worktree.Path
+	}
+	// Plan every target first so one bad destination moves nothing.
+	results := make([]moveOutput, 0, len(entries))
+	for _, entry := range entries {
+		result, err := planMove(entry, "")
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Selector(), err)
+		}
+		results = append(results, result)
+	}
+	if !dryRun {
+		for index, entry := range entries {
+			if !results[index].Moved {
+				continue
+			}
+			result, err := a.moveWorktree(cmd, entry, "")
+			if err != nil {
+				return fmt.Errorf("%s: %w", entry.Selector(), err)
+			}
+			results[index] = result
+		}
+	}
+
+	if a.jsonOutput {
+		output := adoptOutput{Version: 1, DryRun: dryRun, Adopted: []moveOutput{}, WouldAdopt: []moveOutput{}}
+		if dryRun {
+			output.WouldAdopt = results
+		} else {
+			output.Adopted = results
+		}
+		return writeJSON(cmd, output)
+	}
+	action := "Adopted"
+	if dryRun {
+		action = "Would adopt"
+	}
+	// With --null, stdout carries only the new path of the worktree the
+	// command ran in, so the summary moves to stderr.
+	out := cmd.OutOrStdout()
+	if a.nullOutput {
+		out = cmd.ErrOrStderr()
+	}
+	style := a.style(out)
+	returnPath := ""
+	for _, result := range results {
+		selector := result.Repository + ":" + result.Branch
+		if result.Moved {
+			fmt.Fprintf(out, "%s %s  %s\n", style.info(action), style.branch(selector), style.muted(result.Path))
+		} else {
+			fmt.Fprintf(out, "%s %s\n", style.branch(selector), style.muted("already at "+result.Path))
+		}
+		if result.Moved && result.PreviousPath == current {
+			returnPath = result.Path
+		}
+	}
+	if a.nullOutput && returnPath != "" {
+		return a.writePath(cmd, returnPath)
+	}
+	return nil
+}
+
+// outsideManagedLayout reports whether a worktree sits somewhere other than
+// its branch's path under .wt.
+func outsideManagedLayout(entry *inventory.Entry) bool {
+	if entry.Worktree.Branch == "" {
+		return false
+	}
+	path, err := entry.Repository.Git.ManagedPath(entry.Worktree.Branch)
+	return err == nil && path != entry.Worktree.Path
+}
+
+// planMove checks that the worktree can move to newBranch's managed path, or
+// its own branch's when newBranch is empty, without changing anything.
+func planMove(entry *inventory.Entry, newBranch string) (moveOutput, error) {
+	worktree := entry.Worktree
+	if worktree.Main {
+		return moveOutput{}, fmt.Errorf("refusing to move the main worktree")
+	}
+	if worktree.Branch == "" {
+		return moveOutput{}, fmt.Errorf("worktree is detached; check out a branch before moving it")
+	}
+	if worktree.Locked {
+		return moveOutput{}, lockedError(entry)
+	}
+	if newBranch == "" {
+		newBranch = worktree.Branch
+	}
+	git := entry.Repository.Git
+	if newBranch != worktree.Branch && git.RefExists("refs/heads/"+newBranch) {
+		return moveOutput{}, fmt.Errorf("branch %q already exists", newBranch)
+	}
+	path, err := git.PlanWorktreeMove(worktree.Path, newBranch)
+	if err != nil {
+		return moveOutput{}, err
+	}
+	return moveOutput{
+		Version:        1,
+		Repository:     entry.Repository.Name,
+		Branch:         newBranch,
+		PreviousBranch: worktree.Branch,
+		Path:           path,
+		PreviousPath:   worktree.Path,
+		Moved:          path != worktree.Path,
+	}, nil
+}
+
+func (a *application) moveWorktree(cmd *cobra.Command, entry *inventory.Entry, newBranch string) (moveOutput, error) {
+	output, err := planMove(entry, newBranch)
+	if err != nil {
+		return moveOutput{}, err
+	}
+	worktree := entry.Worktree
+	git := entry.Repository.Git
+	renamed := output.Branch != worktree.Branch
+	if renamed {
+		if err := git.RenameBranch(worktree.Branch, output.Branch); err != nil {
+			return moveOutput{}, err
+		}
+	}
+	path, err := git.MoveWorktree(worktree.Path, output.Branch)
+	if err != nil {
+		if renamed {
+			// Leave the branch and directory agreeing with each other.
+			if undoErr := git.RenameBranch(output.Branch, worktree.Branch); undoErr != nil {
+				return moveOutput{}, fmt.Errorf("%w; restoring branch %s: %v", err, worktree.Branch, undoErr)
+			}
+		}
+		return moveOutput{}, err
+	}
+	output.Path = path
+	output.Moved = path != worktree.Path
+	if output.Moved {
+		// Recency is presentation state; a lost rank must not fail a completed move.
+		if err := a.dependencies.moveVisited(worktree.Path, path); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: moving navigation recency: %v\n", err)
+		}
+	}
+	return output, nil
+}
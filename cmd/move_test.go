@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMoveRenamesBranchAndRelocatesWorktree(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	parentPath, _, err := executeV2(root, "new", "auth")
+	if err != nil {
+		t.Fatalf("new auth error = %v", err)
+	}
+	parentPath = strings.TrimSpace(parentPath)
+	if err := os.WriteFile(filepath.Join(parentPath, "draft.txt"), []byte("draft"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return parentPath, nil }})
+	if _, _, err := executeV2(root, "new", "--stack", "auth-ui"); err != nil {
+		t.Fatalf("new --stack error = %v", err)
+	}
+
+	var movedFrom, movedTo string
+	root = newRootCommand(commandDependencies{
+		getwd: func() (string, error) { return repoPath, nil },
+		moveVisited: func(from, to string) error {
+			movedFrom, movedTo = from, to
+			return nil
+		},
+	})
+	stdout, _, err := executeV2(root, "--json", "mv", "feat/auth", "feat/login")
+	if err != nil {
+		t.Fatalf("mv error = %v", err)
+	}
+	var output moveOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	wantPath := filepath.Join(canonicalV2Path(t, repoPath), ".wt", "feat", "login")
+	if !output.Moved || output.Path != wantPath || output.PreviousPath != parentPath || output.PreviousBranch != "feat/auth" {
+		t.Fatalf("mv output = %#v", output)
+	}
+	if movedFrom != parentPath || movedTo != wantPath {
+		t.Fatalf("recency moved %q -> %q", movedFrom, movedTo)
+	}
+	if data, err := os.ReadFile(filepath.Join(wantPath, "draft.txt")); err != nil || string(data) != "draft" {
+		t.Fatalf("moved draft = %q, %v", data, err)
+	}
+	if branch := v2GitOutput(t, wantPath, "branch", "--show-current"); branch != "feat/login" {
+		t.Fatalf("branch after mv = %q", branch)
+	}
+	if parent := v2GitOutput(t, repoPath, "config", "branch.feat/auth-ui.grove-parent"); parent != "feat/login" {
+		t.Fatalf("stacked parent after mv = %q", parent)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "mv", "feat/login", "feat/auth-ui"); err == nil {
+		t.Fatal("mv onto an existing branch succeeded")
+	}
+}
+
+func TestAdoptMovesExternalWorktreeIntoManagedLayout(t *testing.T) {
+	repoPath := initV2Repo(t)
+	externalPath := filepath.Join(t.TempDir(), "legacy")
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/legacy", externalPath)
+	writeV2Config(t, repoPath, "")
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "adopt", "--dry-run", externalPath)
+	if err != nil {
+		t.Fatalf("adopt --dry-run error = %v", err)
+	}
+	var output adoptOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	wantPath := filepath.Join(canonicalV2Path(t, repoPath), ".wt", "feat", "legacy")
+	if len(output.WouldAdopt) != 1 || !output.WouldAdopt[0].Moved || output.WouldAdopt[0].Path != wantPath || len(output.Adopted) != 0 {
+		t.Fatalf("adopt --dry-run = %#v", output)
+	}
+	if _, err := os.Stat(externalPath); err != nil {
+		t.Fatalf("dry run moved the worktree: %v", err)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--color=never", "adopt", "feat/legacy")
+	if err != nil || !strings.Contains(stdout, "Adopted app:feat/legacy  "+wantPath) {
+		t.Fatalf("adopt = %v, stdout %q", err, stdout)
+	}
+	if _, err := os.Stat(externalPath); !os.IsNotExist(err) {
+		t.Fatalf("external worktree remains: %v", err)
+	}
+	if listing := v2GitOutput(t, repoPath, "worktree", "list", "--porcelain"); !strings.Contains(listing, wantPath) {
+		t.Fatalf("worktree list after adopt:\n%s", listing)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--color=never", "adopt", "feat/legacy")
+	if err != nil || !strings.Contains(stdout, "already at "+wantPath) {
+		t.Fatalf("adopt again = %v, stdout %q", err, stdout)
+	}
+}
+
+func TestAdoptNullPrintsNewPathOfCurrentWorktree(t *testing.T) {
+	repoPath := initV2Repo(t)
+	externalPath := filepath.Join(t.TempDir(), "legacy")
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/legacy", externalPath)
+	writeV2Config(t, repoPath, "")
+	wantPath := filepath.Join(canonicalV2Path(t, repoPath), ".wt", "feat", "legacy")
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return externalPath, nil }})
+	stdout, stderr, err := executeV2(root, "--null", "adopt", ".")
+	if err != nil || stdout != wantPath+"\x00" || !strings.Contains(stderr, "Adopted app:feat/legacy") {
+		t.Fatalf("adopt --null = %v, stdout %q, stderr %q", err, stdout, stderr)
+	}
+
+	otherPath := filepath.Join(t.TempDir(), "other")
+	runV2Git(t, repoPath, "worktree", "add", "-b", "feat/other", otherPath)
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if stdout, _, err := executeV2(root, "--null", "adopt", "feat/other"); err != nil || stdout != "" {
+		t.Fatalf("adopt --null from elsewhere = %v, stdout %q", err, stdout)
+	}
+}
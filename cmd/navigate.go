@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"grove/internal/catalog"
 	"grove/internal/inventory"
 	"grove/internal/picker"
 
@@ -22,28 +26,31 @@ type worktreeOutput struct {
 }
 
 func (a *application) cdCommand() *cobra.Command {
-	return &cobra.Command{
+	var fuzzy bool
+	command := &cobra.Command{
 		Use:   "cd [selector]",
 		Short: "Print a worktree path",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return a.runNavigate(cmd, args)
+			return a.runNavigate(cmd, args, fuzzy)
 		},
 	}
+	command.Flags().BoolVar(&fuzzy, "fuzzy", false, "Match the selector loosely, as if it were written ~selector")
+	return command
 }
 
-func (a *application) runNavigate(cmd *cobra.Command, args []string) error {
+func (a *application) runNavigate(cmd *cobra.Command, args []string, fuzzy bool) error {
 	context, err := a.loadContext(cmd)
 	if err != nil {
 		return err
 	}
 	var entry *inventory.Entry
 	if len(args) == 1 {
-		entry, err = context.inventory.Resolve(args[0], context.directory)
+		entry, err = a.resolveSelector(context, args[0], fuzzy)
 	} else {
-		entry, err = a.pickWorktree(context, "worktree > ")
+		entry, err = a.pickNavigation(cmd, context)
 	}
-	if err != nil {
+	if err != nil || entry == nil {
 		return err
 	}
 	if err := a.writeWorktree(cmd, entry); err != nil {
@@ -56,20 +63,217 @@ func (a *application) runNavigate(cmd *cobra.Command, args []string) error {
 		if err := a.dependencies.markVisited(entry.Worktree.Path); err != nil {
 			fmt.Fprintf(cmd.ErrOrStderr(), "warning: recording navigation recency: %v\n", err)
 		}
+		runPostVisitHook(cmd, entry)
 	}
 	return nil
 }
 
-func (a *application) pickWorktree(context *commandContext, prompt string) (*inventory.Entry, error) {
+// resolveSelector resolves a selector given on the command line, including
+// the - and @-N recency selectors. A fuzzy query that matches several
+// worktrees opens the picker on just those when interactive; exact selectors
+// resolve strictly, as they always have.
+func (a *application) resolveSelector(context *commandContext, selector string, fuzzy bool) (*inventory.Entry, error) {
+	if repositoryName, back, ok := parseRecentSelector(selector); ok {
+		return a.resolveRecent(context, repositoryName, back)
+	}
+	if fuzzy {
+		selector = inventory.Fuzzy(selector)
+	}
+	entry, err := context.inventory.Resolve(selector, context.directory)
+	var ambiguous *inventory.AmbiguousError
+	if !errors.As(err, &ambiguous) || a.noInput || !a.dependencies.interactive() {
+		return entry, err
+	}
+	selection, err := a.dependencies.pick(ambiguous.Query+" > ", a.navigationPickerItems(ambiguous.Matches), true)
+	if err != nil {
+		return nil, err
+	}
+	return context.inventory.Resolve(selection.Key, context.directory)
+}
+
+// parseRecentSelector recognizes selectors that count back through visits:
+// "-" and "@-N", the latter optionally after a repo: prefix. "-" is @-1.
+func parseRecentSelector(selector string) (string, int, bool) {
+	if selector == "-" {
+		return "", 1, true
+	}
+	repositoryName, rest, found := strings.Cut(selector, ":")
+	if !found {
+		repositoryName, rest = "", selector
+	}
+	digits, found := strings.CutPrefix(rest, "@-")
+	if !found || digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", 0, false
+	}
+	back, err := strconv.Atoi(digits)
+	if err != nil || back < 1 {
+		return "", 0, false
+	}
+	return repositoryName, back, true
+}
+
+// resolveRecent returns the worktree visited back visits ago, as cd - does:
+// the worktree containing the current directory is skipped, and so are
+// visited paths that are no longer worktrees.
+func (a *application) resolveRecent(context *commandContext, repositoryName string, back int) (*inventory.Entry, error) {
+	var repository *catalog.Repository
+	if repositoryName != "" {
+		found, _, err := context.catalog.FindRepository(repositoryName)
+		if err != nil {
+			return nil, err
+		}
+		repository = found
+	}
+	visits, err := a.dependencies.recent()
+	if err != nil {
+		return nil, fmt.Errorf("reading visit history: %w", err)
+	}
+	current := ""
+	if entry, err := context.inventory.Resolve(".", context.directory); err == nil {
+		current = entry.Worktree.Path
+	}
+	byPath := make(map[string]*inventory.Entry, len(context.inventory.Entries))
+	for _, entry := range context.inventory.Entries {
+		if !entry.Worktree.Prunable && (repository == nil || entry.Repository == repository) {
+			byPath[entry.Worktree.Path] = entry
+		}
+	}
+	remaining := back
+	for _, visit := range visits {
+		entry := byPath[visit.Path]
+		if entry == nil || entry.Worktree.Path == current {
+			continue
+		}
+		if remaining--; remaining == 0 {
+			return entry, nil
+		}
+	}
+	if back-remaining == 0 {
+		return nil, fmt.Errorf("no previously visited worktree")
+	}
+	return nil, fmt.Errorf("only %s visited before this one", worktreeCount(back-remaining, "worktree", "worktrees"))
+}
+
+// Navigation picker actions. Each hands over to the command it stands for, so
+// acting from the picker skips none of that command's checks.
+const (
+	navigateNew  = "new"
+	navigateMark = "mark"
+	navigateLock = "lock"
+)
+
+var navigationActions = []picker.Action{
+	{Key: "ctrl-n", Name: navigateNew},
+	{Key: "ctrl-x", Name: navigateMark},
+	{Key: "ctrl-l", Name: navigateLock},
+}
+
+// pickNavigation returns the picked worktree, or nil once an action has
+// written its own output. New creates a worktree from the typed query, printing
+// its path as `grove new` does. Mark marks or unmarks the highlighted
+// worktree; with any marked, Enter asks to confirm and then removes them all as
+// `grove rm` does. Lock toggles the highlighted worktree's lock. Marking and
+// locking reopen the picker.
+func (a *application) pickNavigation(cmd *cobra.Command, context *commandContext) (*inventory.Entry, error) {
 	if a.noInput || !a.dependencies.interactive() {
 		return nil, fmt.Errorf("selector is required in non-interactive mode")
 	}
-	items := a.navigationPickerItems(context.inventory)
-	path, err := a.dependencies.pick(prompt, items)
+	var actions []picker.Action
+	if !a.jsonOutput {
+		actions = navigationActions
+	}
+	var marked []string
+	for {
+		items := a.navigationPickerItems(context.inventory.Entries)
+		for index := range items {
+			if slices.Contains(marked, items[index].Key) {
+				items[index].Label += "  (marked for removal)"
+			}
+		}
+		selection, err := a.dependencies.pick("worktree > ", items, true, actions...)
+		if err != nil {
+			return nil, err
+		}
+		switch selection.Action {
+		case navigateNew:
+			var args []string
+			if query := strings.TrimSpace(selection.Query); query != "" {
+				args = []string{query}
+			}
+			return nil, a.runNew(cmd, args, newOptions{})
+		case navigateMark:
+			if position := slices.Index(marked, selection.Key); position >= 0 {
+				marked = slices.Delete(marked, position, position+1)
+			} else if selection.Key != "" {
+				marked = append(marked, selection.Key)
+			}
+			continue
+		case navigateLock:
+			if selection.Key != "" {
+				a.toggleLock(cmd, context, selection.Key)
+			}
+			if context, err = a.loadContext(cmd); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(marked) != 0 {
+			confirmed, err := a.confirmRemoval(context, marked)
+			if err != nil {
+				return nil, err
+			}
+			if confirmed {
+				return nil, a.runRemove(cmd, marked, removeOptions{trash: true})
+			}
+			continue
+		}
+		return context.inventory.Resolve(selection.Key, context.directory)
+	}
+}
+
+// confirmRemoval asks whether to remove the worktrees marked in the picker.
+// Backing out, including cancelling, returns to the list with the marks kept.
+func (a *application) confirmRemoval(context *commandContext, marked []string) (bool, error) {
+	selectors := make([]string, 0, len(marked))
+	for _, path := range marked {
+		if entry, err := context.inventory.Resolve(path, context.directory); err == nil {
+			selectors = append(selectors, entry.Selector())
+		}
+	}
+	items := []picker.Item{
+		{Key: "back", Label: "Back to the worktree list"},
+		{Key: "remove", Label: "Remove " + strings.Join(selectors, ", ")},
+	}
+	selection, err := a.dependencies.pick("remove "+worktreeCount(len(marked), "worktree", "worktrees")+"? > ", items, false)
+	if errors.Is(err, picker.ErrCancelled) {
+		return false, nil
+	}
+	return selection.Key == "remove", err
+}
+
+// toggleLock locks or unlocks one worktree from the picker. Its notice goes
+// to stderr, since stdout carries only the path navigation ends on.
+func (a *application) toggleLock(cmd *cobra.Command, context *commandContext, key string) {
+	entry, err := context.inventory.Resolve(key, context.directory)
+	if err == nil && entry.Worktree.Main {
+		err = fmt.Errorf("%s: the main worktree cannot be locked or unlocked", entry.Selector())
+	}
+	var result lockResult
+	if err == nil {
+		if result, err = setWorktreeLock(entry, !entry.Worktree.Locked, ""); err != nil {
+			err = fmt.Errorf("%s: %w", entry.Selector(), err)
+		}
+	}
 	if err != nil {
-		return nil, err
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+		return
+	}
+	action := "Unlocked"
+	if result.Locked {
+		action = "Locked"
 	}
-	return context.inventory.Resolve(path, context.directory)
+	style := a.style(cmd.ErrOrStderr())
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s %s\n", style.info(action), style.branch(result.Selector))
 }
 
 type navigationCandidate struct {
@@ -81,10 +285,10 @@ type navigationCandidate struct {
 // navigationPickerItems owns human navigation presentation: paths remain opaque
 // keys, while visible repository/worktree labels are ranked by durable visits and
 // then the same creation-time proxy used by list and age cleanup.
-func (a *application) navigationPickerItems(inv *inventory.Inventory) []picker.Item {
-	candidates := make([]navigationCandidate, 0, len(inv.Entries))
+func (a *application) navigationPickerItems(entries []*inventory.Entry) []picker.Item {
+	candidates := make([]navigationCandidate, 0, len(entries))
 	repositoryWidth := 0
-	for _, entry := range inv.Entries {
+	for _, entry := range entries {
 		if entry.Worktree.Prunable {
 			continue
 		}
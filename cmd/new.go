@@ -2,10 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"grove/internal/catalog"
 	"grove/internal/config"
@@ -16,25 +16,69 @@ import (
 )
 
 type newOutput struct {
-	Version    int    `json:"version"`
-	Repository string `json:"repository"`
-	Branch     string `json:"branch"`
-	Path       string `json:"path"`
-	Created    bool   `json:"created"`
+	Version     int    `json:"version"`
+	Repository  string `json:"repository"`
+	Branch      string `json:"branch"`
+	Path        string `json:"path"`
+	Created     bool   `json:"created"`
+	StartPoint  string `json:"start_point,omitempty"`
+	PullRequest int    `json:"pull_request,omitempty"`
+	// Files, Clones, and Setup report the copy/link patterns, clone_dirs, and
+	// setup steps applied to a newly created worktree. Removed is set when
+	// setup_failure: remove discarded the worktree after a failure.
+	Files   []worktreeFileResult `json:"files,omitempty"`
+	Clones  []cloneResult        `json:"clones,omitempty"`
+	Setup   []setupStepResult    `json:"setup,omitempty"`
+	Hooks   []setupStepResult    `json:"hooks,omitempty"`
+	Removed bool                 `json:"removed,omitempty"`
 }
 
+type newOptions struct {
+	from  string
+	stack bool
+	pr    int
+	fetch bool
+}
+
+// defaultPullRef is GitHub's pull request head; repositories on other forges
+// set pull_ref, e.g. refs/merge-requests/{number}/head for GitLab.
+const defaultPullRef = "refs/pull/{number}/head"
+
 func (a *application) newCommand() *cobra.Command {
-	return &cobra.Command{
+	var options newOptions
+	command := &cobra.Command{
 		Use:   "new [branch]",
 		Short: "Create or find a worktree",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return a.runNew(cmd, args)
+			if cmd.Flags().Changed("from") && options.from == "" {
+				return fmt.Errorf("--from requires a commit, tag, branch, or repo:branch selector")
+			}
+			if options.stack && options.from != "" {
+				return fmt.Errorf("--stack and --from cannot be used together")
+			}
+			if options.fetch && (options.stack || options.from != "") {
+				return fmt.Errorf("--fetch only applies to branches started from the default branch; it cannot be combined with --from or --stack")
+			}
+			if cmd.Flags().Changed("pr") {
+				if options.pr < 1 {
+					return fmt.Errorf("--pr requires a positive pull request number")
+				}
+				if options.stack || options.from != "" {
+					return fmt.Errorf("--pr cannot be combined with --from or --stack")
+				}
+			}
+			return a.runNew(cmd, args, options)
 		},
 	}
+	command.Flags().StringVar(&options.from, "from", "", "Start a new branch from a commit, tag, branch, or repo:branch worktree")
+	command.Flags().BoolVar(&options.stack, "stack", false, "Start a new branch from the current worktree's branch and record it as the parent")
+	command.Flags().BoolVar(&options.fetch, "fetch", false, "Fetch the repository's remote before choosing the default branch start point")
+	command.Flags().IntVar(&options.pr, "pr", 0, "Fetch a pull request head into the pr/<number> branch")
+	return command
 }
 
-func (a *application) runNew(cmd *cobra.Command, args []string) error {
+func (a *application) runNew(cmd *cobra.Command, args []string, options newOptions) error {
 	context, err := a.loadContext(cmd)
 	if err != nil {
 		return err
@@ -47,7 +91,17 @@ func (a *application) runNew(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if branch == "" {
+	pullRef := ""
+	if options.pr != 0 {
+		if branch != "" {
+			return fmt.Errorf("--pr names the branch itself; pass at most a repo: prefix")
+		}
+		branch = fmt.Sprintf("pr/%d", options.pr)
+		pullRef, err = pullRequestRef(repository.PullRef, options.pr)
+		if err != nil {
+			return err
+		}
+	} else if branch == "" {
 		branch = generateBranch(repository)
 	} else if !strings.Contains(branch, "/") {
 		branch = "feat/" + branch
@@ -59,33 +113,173 @@ func (a *application) runNew(cmd *cobra.Command, args []string) error {
 	if err := a.validatePathOutput(outputPath); err != nil {
 		return err
 	}
+	startPoint, parent := "", ""
+	if options.from != "" || options.stack {
+		if _, remote := repository.Git.RemoteBranch(branch); remote || repository.Git.RefExists("refs/heads/"+branch) {
+			return fmt.Errorf("branch %q already exists; --from and --stack only apply to new branches", branch)
+		}
+	}
+	if options.from != "" {
+		startPoint, err = resolveStartPoint(context, repository, options.from)
+		if err != nil {
+			return err
+		}
+	}
+	if options.stack {
+		parent, err = stackParent(context, repository)
+		if err != nil {
+			return err
+		}
+		startPoint = "refs/heads/" + parent
+	}
+	branchExisted := repository.Git.RefExists("refs/heads/" + branch)
 	if context.catalog.Current == repository && !context.catalog.CurrentRegistered {
 		if err := registerRepository(context.catalog, repository); err != nil {
 			return fmt.Errorf("registering repository: %w", err)
 		}
 		context.catalog.CurrentRegistered = true
 	}
+	if pullRef != "" && !repository.Git.RefExists("refs/heads/"+branch) {
+		if err := repository.Git.FetchBranch(repository.Git.Remote(), pullRef, branch); err != nil {
+			return fmt.Errorf("fetching pull request %d: %w", options.pr, err)
+		}
+	}
 
-	startPoint := ""
-	if !repository.Git.RefExists("refs/heads/"+branch) && !repository.Git.RefExists("refs/remotes/origin/"+branch) {
-		startPoint, err = repository.Git.BaseRef(repository.DefaultBranch)
+	fetched := false
+	if startPoint == "" && !repository.Git.RefExists("refs/heads/"+branch) {
+		// Fetching may also reveal the branch on the remote, which is then tracked.
+		fetched = fetchRepositories(cmd, []*catalog.Repository{repository}, options.fetch)[repository]
+	}
+	if _, remote := repository.Git.RemoteBranch(branch); startPoint == "" && !remote && !repository.Git.RefExists("refs/heads/"+branch) {
+		if fetched {
+			startPoint, err = repository.Git.FreshBaseRef(repository.DefaultBranch)
+		} else {
+			startPoint, err = repository.Git.BaseRef(repository.DefaultBranch)
+		}
 		if err != nil {
 			return err
 		}
 	}
 	path, created, err := repository.Git.CreateWorktree(branch, startPoint)
 	if err != nil {
+		if pullRef != "" && !branchExisted {
+			// The branch only existed for this worktree; do not leave it behind.
+			if err := repository.Git.DeleteBranch(branch); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: deleting branch %s: %v\n", branch, err)
+			}
+		}
 		return fmt.Errorf("creating worktree: %w", err)
 	}
+	if created && !branchExisted {
+		if err := repository.Git.MarkBranchCreated(branch, time.Now()); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: recording created branch: %v\n", err)
+		}
+	}
+	if created && parent != "" {
+		if err := repository.Git.SetBranchParent(branch, parent); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: recording stack parent: %v\n", err)
+		}
+	}
+	if pullRef != "" {
+		startPoint = pullRef
+	}
+	output := newOutput{Version: 1, Repository: repository.Name, Branch: branch, Path: path, Created: created, StartPoint: startPoint, PullRequest: options.pr}
+	var setupErr error
 	if created {
-		runSetup(cmd, path, profile)
+		output.Files = copyWorktreeFiles(cmd, repository, profile, path)
+		output.Clones = a.cloneWorktreeDirs(cmd, repository, profile, path)
+		output.Setup, setupErr = runSetup(cmd, setupTarget{repository: repository, profile: profile, branch: branch, path: path})
+		if setupErr != nil {
+			setupErr = applySetupFailure(cmd, repository, profile, branch, path, branchExisted, setupErr, &output)
+		}
+		if setupErr == nil {
+			output.Hooks = runPostCreateHook(cmd, setupTarget{repository: repository, profile: profile, branch: branch, path: path})
+		}
 	}
 	if a.jsonOutput {
-		return writeJSON(cmd, newOutput{Version: 1, Repository: repository.Name, Branch: branch, Path: path, Created: created})
+		if err := writeJSON(cmd, output); err != nil {
+			return err
+		}
+		return setupErr
+	}
+	if setupErr != nil {
+		return setupErr
 	}
 	return a.writePath(cmd, path)
 }
 
+// applySetupFailure carries out the profile's setup_failure policy and returns
+// the error `grove new` should exit with, nil when the policy only warns.
+func applySetupFailure(cmd *cobra.Command, repository *catalog.Repository, profile *catalog.Profile, branch, path string, branchExisted bool, setupErr error, output *newOutput) error {
+	switch profile.SetupFailure {
+	case config.SetupFailureFail:
+		return fmt.Errorf("%w; worktree kept at %s", setupErr, path)
+	case config.SetupFailureRemove:
+		if err := repository.Git.RemoveWorktree(path, true); err != nil {
+			return fmt.Errorf("%w; removing worktree %s: %v", setupErr, path, err)
+		}
+		output.Removed = true
+		if !branchExisted {
+			if err := repository.Git.DeleteBranch(branch); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: deleting branch %s: %v\n", branch, err)
+			}
+		}
+		return fmt.Errorf("%w; removed worktree %s", setupErr, path)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", setupErr)
+	return nil
+}
+
+// pullRequestRef expands a pull_ref pattern for one pull request number.
+func pullRequestRef(pattern string, number int) (string, error) {
+	if pattern == "" {
+		pattern = defaultPullRef
+	}
+	if !strings.Contains(pattern, "{number}") {
+		return "", fmt.Errorf("pull_ref %q must contain {number}", pattern)
+	}
+	return strings.ReplaceAll(pattern, "{number}", strconv.Itoa(number)), nil
+}
+
+// stackParent returns the branch of the worktree containing the working
+// directory, which must belong to the repository receiving the new branch.
+func stackParent(context *commandContext, repository *catalog.Repository) (string, error) {
+	current, err := context.inventory.Resolve(".", context.directory)
+	if err != nil {
+		return "", fmt.Errorf("--stack: %w", err)
+	}
+	if current.Repository != repository {
+		return "", fmt.Errorf("--stack: current worktree belongs to repository %s, not %s", current.Repository.Name, repository.Name)
+	}
+	if current.Worktree.Branch == "" {
+		return "", fmt.Errorf("--stack: current worktree %s is detached", current.Selector())
+	}
+	return current.Worktree.Branch, nil
+}
+
+// resolveStartPoint accepts any commit-ish of the target repository, or a
+// repo:branch selector naming another worktree of that same repository.
+func resolveStartPoint(context *commandContext, repository *catalog.Repository, from string) (string, error) {
+	if !strings.Contains(from, ":") {
+		startPoint, err := repository.Git.ResolveStartPoint(from)
+		if err != nil {
+			return "", fmt.Errorf("--from: %w", err)
+		}
+		return startPoint, nil
+	}
+	entry, err := context.inventory.Resolve(from, context.directory)
+	if err != nil {
+		return "", fmt.Errorf("--from: %w", err)
+	}
+	if entry.Repository != repository {
+		return "", fmt.Errorf("--from: %s belongs to repository %s, not %s", from, entry.Repository.Name, repository.Name)
+	}
+	if entry.Worktree.Branch != "" {
+		return "refs/heads/" + entry.Worktree.Branch, nil
+	}
+	return entry.Worktree.Head, nil
+}
+
 func resolveNewTarget(cat *catalog.Catalog, raw string) (*catalog.Repository, *catalog.Profile, string, error) {
 	if strings.Contains(raw, ":") {
 		parts := strings.SplitN(raw, ":", 2)
@@ -112,7 +306,7 @@ func generateBranch(repository *catalog.Repository) string {
 	}
 	for {
 		branch := names.GenerateBranch(existing)
-		if !repository.Git.RefExists("refs/heads/"+branch) && !repository.Git.RefExists("refs/remotes/origin/"+branch) {
+		if _, remote := repository.Git.RemoteBranch(branch); !remote && !repository.Git.RefExists("refs/heads/"+branch) {
 			return branch
 		}
 		existing = append(existing, branch)
@@ -127,63 +321,7 @@ func registerRepository(cat *catalog.Catalog, repository *catalog.Repository) er
 	name := cat.UniqueName(filepath.Base(repository.Git.MainPath))
 	defaultBranch := repository.DefaultBranch
 	if defaultBranch == "" {
-		defaultBranch = gitx.DefaultBranch(repository.Git.MainPath)
+		defaultBranch = gitx.DefaultBranch(repository.Git.MainPath, repository.Git.Remote())
 	}
 	return config.AddRepoToFile(path, config.NewWorktreeRepo(repository.Git.MainPath, name, defaultBranch))
 }
-
-func runSetup(cmd *cobra.Command, worktreePath string, profile *catalog.Profile) {
-	if profile == nil || len(profile.Setup) == 0 {
-		return
-	}
-	directory, err := setupDirectory(worktreePath, profile.Workdir)
-	if err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "warning: setup skipped: %v\n", err)
-		return
-	}
-	for _, instruction := range profile.Setup {
-		fmt.Fprintf(cmd.ErrOrStderr(), "setup: %s\n", instruction)
-		process := exec.Command("sh", "-c", instruction)
-		process.Dir = directory
-		process.Stdout = cmd.ErrOrStderr()
-		process.Stderr = cmd.ErrOrStderr()
-		process.Stdin = os.Stdin
-		if err := process.Run(); err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "warning: setup command failed: %v\n", err)
-		}
-	}
-}
-
-func setupDirectory(worktreePath, workdir string) (string, error) {
-	root, err := filepath.EvalSymlinks(worktreePath)
-	if err != nil {
-		return "", fmt.Errorf("resolving worktree: %w", err)
-	}
-	if workdir == "" {
-		return root, nil
-	}
-	if filepath.IsAbs(workdir) {
-		return "", fmt.Errorf("workdir must be relative: %s", workdir)
-	}
-	directory := filepath.Clean(filepath.Join(root, workdir))
-	rel, err := filepath.Rel(root, directory)
-	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
-		return "", fmt.Errorf("workdir escapes the worktree: %s", workdir)
-	}
-	info, err := os.Stat(directory)
-	if err != nil {
-		return "", fmt.Errorf("workdir %s: %w", workdir, err)
-	}
-	if !info.IsDir() {
-		return "", fmt.Errorf("workdir is not a directory: %s", workdir)
-	}
-	resolved, err := filepath.EvalSymlinks(directory)
-	if err != nil {
-		return "", fmt.Errorf("resolving workdir %s: %w", workdir, err)
-	}
-	rel, err = filepath.Rel(root, resolved)
-	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
-		return "", fmt.Errorf("workdir escapes the worktree through a symlink: %s", workdir)
-	}
-	return resolved, nil
-}
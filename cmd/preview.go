@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"grove/internal/inventory"
+	"grove/internal/picker"
+
+	"github.com/spf13/cobra"
+)
+
+// Limits that keep a preview within one fzf preview window.
+const (
+	previewFileLimit   = 20
+	previewCommitLimit = 5
+)
+
+// previewCommand is the hidden subcommand the fzf picker runs for the
+// highlighted item. With --keys, the argument is an index into the picker's
+// NUL-separated key file; otherwise it is a selector.
+func (a *application) previewCommand() *cobra.Command {
+	var keysFile string
+	command := &cobra.Command{
+		Use:    picker.PreviewCommand + " <index|selector>",
+		Short:  "Describe one worktree for the picker preview",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			if keysFile != "" {
+				var err error
+				if key, err = previewKey(keysFile, key); err != nil {
+					return err
+				}
+			}
+			return a.runPreview(cmd, key)
+		},
+	}
+	command.Flags().StringVar(&keysFile, "keys", "", "NUL-separated picker keys the argument indexes")
+	return command
+}
+
+func previewKey(keysFile, index string) (string, error) {
+	data, err := os.ReadFile(keysFile)
+	if err != nil {
+		return "", err
+	}
+	keys := bytes.Split(bytes.TrimSuffix(data, []byte{0}), []byte{0})
+	position, err := strconv.Atoi(index)
+	if err != nil || position < 0 || position >= len(keys) {
+		return "", fmt.Errorf("invalid preview index %q", index)
+	}
+	return string(keys[position]), nil
+}
+
+func (a *application) runPreview(cmd *cobra.Command, key string) error {
+	// Config warnings were already shown by the command that opened the
+	// picker; repeating them on every cursor move would bury the preview.
+	cmd.SetErr(io.Discard)
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	// A preview never prompts, so an ambiguous fuzzy query is just an error.
+	a.noInput = true
+	entry, err := a.resolveSelector(context, key, false)
+	if err != nil {
+		fmt.Fprintf(out, "no preview for %s\n", key)
+		return nil
+	}
+	writePreview(out, a.style(out), entry, time.Now())
+	return nil
+}
+
+func writePreview(out io.Writer, style outputStyle, entry *inventory.Entry, now time.Time) {
+	worktree := entry.Worktree
+	repository := entry.Repository
+	fmt.Fprintln(out, style.heading(entry.Selector()))
+	fmt.Fprintln(out, style.muted(worktree.Path))
+	if worktree.Prunable {
+		fmt.Fprintln(out, style.danger("missing from disk"))
+		return
+	}
+
+	branch := worktree.Branch
+	if branch == "" {
+		branch = "detached at " + shortSHA(worktree.Head)
+	}
+	line := "branch " + style.branch(branch)
+	if base, err := repository.Git.BaseRef(repository.DefaultBranch); err == nil && !worktree.Main {
+		if ahead, behind, err := repository.Git.AheadBehindRef(worktree.Path, base); err == nil {
+			line += fmt.Sprintf("  ↑%d ↓%d vs %s", ahead, behind, base)
+		}
+	}
+	fmt.Fprintln(out, line)
+	status, statusErr := repository.Git.Status(worktree.Path)
+	if statusErr == nil {
+		switch {
+		case status.UpstreamGone:
+			fmt.Fprintln(out, style.attention("upstream "+status.Upstream+" is gone"))
+		case status.Upstream != "":
+			fmt.Fprintf(out, "upstream %s  ⇡%d ⇣%d\n", status.Upstream, status.UpstreamAhead, status.UpstreamBehind)
+		}
+		if status.Operation != "" {
+			fmt.Fprintln(out, style.attention(status.Operation+" in progress"))
+		}
+	}
+	if label := worktreeCreationLabel(worktree.Path, now); label != "" && !worktree.Main {
+		fmt.Fprintln(out, style.muted(label))
+	}
+	if worktree.Locked {
+		reason := worktree.LockReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		fmt.Fprintln(out, style.attention("locked: "+reason))
+	}
+
+	fmt.Fprintln(out)
+	switch changes, err := repository.Git.ChangedFiles(worktree.Path); {
+	case err != nil:
+		fmt.Fprintln(out, style.danger("status: "+err.Error()))
+	case len(changes) == 0:
+		fmt.Fprintln(out, style.muted("clean"))
+	default:
+		fmt.Fprintln(out, style.heading(worktreeCount(len(changes), "changed file", "changed files")))
+		for _, change := range changes[:min(len(changes), previewFileLimit)] {
+			fmt.Fprintln(out, "  "+change)
+		}
+		if len(changes) > previewFileLimit {
+			fmt.Fprintln(out, style.muted(fmt.Sprintf("  … and %d more", len(changes)-previewFileLimit)))
+		}
+	}
+
+	commits, err := repository.Git.RecentCommits(worktree.Path, previewCommitLimit)
+	if err != nil || len(commits) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, style.heading("recent commits"))
+	for _, commit := range commits {
+		fmt.Fprintf(out, "  %s %s  %s\n", style.info(shortSHA(commit.Hash)), style.muted(fmt.Sprintf("%4s", relativeAge(now.Sub(commit.At)))), commit.Subject)
+	}
+}
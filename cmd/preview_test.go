@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreviewDescribesTheIndexedWorktree(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "new", "preview")
+	if err != nil {
+		t.Fatalf("new error = %v", err)
+	}
+	worktreePath := strings.TrimSpace(stdout)
+	if err := os.WriteFile(filepath.Join(worktreePath, "notes.txt"), []byte("draft\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runV2Git(t, worktreePath, "commit", "--allow-empty", "-m", "Start the preview work")
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "lock", "--reason", "agent run 7", "feat/preview"); err != nil {
+		t.Fatalf("lock error = %v", err)
+	}
+
+	// The key file holds opaque picker keys; this one is a path with a tab
+	// and newline that would not survive fzf's field splitting.
+	keysFile := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(keysFile, []byte("/no/such\tkey\n\x00"+worktreePath+"\x00"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "__preview", "--keys", keysFile, "1")
+	if err != nil {
+		t.Fatalf("__preview error = %v", err)
+	}
+	for _, want := range []string{"app:feat/preview", "branch feat/preview", "↑1 ↓0", "locked: agent run 7", "1 changed file", "?? notes.txt", "recent commits", "Start the preview work"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("preview missing %q:\n%s", want, stdout)
+		}
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "__preview", "--keys", keysFile, "0")
+	if err != nil || !strings.HasPrefix(stdout, "no preview for /no/such") {
+		t.Fatalf("unknown key preview = %v, stdout %q", err, stdout)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "__preview", "--keys", keysFile, "2"); err == nil {
+		t.Fatal("__preview accepted an index past the keys")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package cmd
+
+import "os/exec"
+
+// killProcessGroupOnCancel is only implemented on Unix; elsewhere cancelling
+// kills the shell alone.
+func killProcessGroupOnCancel(process *exec.Cmd) {}
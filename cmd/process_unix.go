@@ -0,0 +1,18 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroupOnCancel starts process in a process group of its own and
+// makes cancelling it kill the whole group, so the children of a timed-out
+// shell do not keep running after it.
+func killProcessGroupOnCancel(process *exec.Cmd) {
+	process.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	process.Cancel = func() error {
+		return syscall.Kill(-process.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,51 @@
+//go:build unix
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"grove/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSetupStepLeavesForegroundGroupOnlyWithTimeout(t *testing.T) {
+	for _, test := range []struct {
+		timeout  string
+		ownGroup bool
+	}{
+		{timeout: "", ownGroup: false},
+		{timeout: "10s", ownGroup: true},
+	} {
+		directory := t.TempDir()
+		cmd := &cobra.Command{}
+		cmd.SetErr(&bytes.Buffer{})
+		step := config.SetupStep{Run: "echo $$ $(ps -o pgid= -p $$) > group", Timeout: test.timeout}
+		if _, err := runSetupStep(cmd, directory, os.Environ(), step); err != nil {
+			t.Fatalf("timeout %q: runSetupStep error = %v", test.timeout, err)
+		}
+		data, err := os.ReadFile(filepath.Join(directory, "group"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields := strings.Fields(string(data))
+		if len(fields) != 2 {
+			t.Fatalf("timeout %q: group output = %q", test.timeout, data)
+		}
+		pid, _ := strconv.Atoi(fields[0])
+		group, _ := strconv.Atoi(fields[1])
+		if test.ownGroup && group != pid {
+			t.Fatalf("timeout %q: step ran in group %d, want its own group %d", test.timeout, group, pid)
+		}
+		if !test.ownGroup && group != syscall.Getpgrp() {
+			t.Fatalf("timeout %q: step ran in group %d, want the caller's group %d", test.timeout, group, syscall.Getpgrp())
+		}
+	}
+}
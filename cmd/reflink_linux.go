@@ -0,0 +1,29 @@
+//go:build linux
+
+package cmd
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflinkFile creates destination as a copy-on-write clone of source with the
+// FICLONE ioctl, which Btrfs, XFS, and bcachefs support.
+func reflinkFile(source, destination string, mode os.FileMode) error {
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	output, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlFileClone(int(output.Fd()), int(input.Fd())); err != nil {
+		output.Close()
+		os.Remove(destination)
+		return err
+	}
+	return output.Close()
+}
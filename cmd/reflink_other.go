@@ -0,0 +1,14 @@
+//go:build !linux
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+// reflinkFile is only implemented on Linux; elsewhere clone_dirs falls back
+// to hard links.
+func reflinkFile(source, destination string, mode os.FileMode) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"grove/internal/catalog"
+	"grove/internal/config"
+	gitx "grove/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+type repoOutput struct {
+	Version       int      `json:"version"`
+	Action        string   `json:"action"`
+	Name          string   `json:"name"`
+	PreviousName  string   `json:"previous_name,omitempty"`
+	Path          string   `json:"path"`
+	DefaultBranch string   `json:"default_branch,omitempty"`
+	Workdir       string   `json:"workdir,omitempty"`
+	Remote        string   `json:"remote,omitempty"`
+	Setup         []string `json:"setup,omitempty"`
+}
+
+type repoAddOptions struct {
+	name          string
+	defaultBranch string
+	workdir       string
+	remote        string
+	setup         []string
+	setupChanged  bool
+}
+
+func (a *application) repoCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "repo",
+		Short: "Manage configured repositories",
+		Args:  cobra.NoArgs,
+	}
+	command.AddCommand(a.repoAddCommand(), a.repoRemoveCommand(), a.repoRenameCommand())
+	return command
+}
+
+func (a *application) repoAddCommand() *cobra.Command {
+	var options repoAddOptions
+	command := &cobra.Command{
+		Use:   "add <path>",
+		Short: "Register a repository or setup profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.setupChanged = cmd.Flags().Changed("setup")
+			return a.runRepoAdd(cmd, args[0], options)
+		},
+	}
+	command.Flags().StringVar(&options.name, "name", "", "Alias used in repo:branch selectors")
+	command.Flags().StringVar(&options.defaultBranch, "default-branch", "", "Branch new worktrees start from")
+	command.Flags().StringVar(&options.workdir, "workdir", "", "Directory inside each worktree where setup runs")
+	command.Flags().StringVar(&options.remote, "remote", "", "Canonical remote for the default branch, fetches, and pull requests (default origin)")
+	command.Flags().StringArrayVar(&options.setup, "setup", nil, "Setup command to run after creating a worktree (repeatable)")
+	return command
+}
+
+func (a *application) repoRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <alias>",
+		Short: "Unregister a repository alias; worktrees and branches are kept",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runRepoRemove(cmd, args[0])
+		},
+	}
+}
+
+func (a *application) repoRenameCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a repository alias",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runRepoRename(cmd, args[0], args[1])
+		},
+	}
+}
+
+func (a *application) runRepoAdd(cmd *cobra.Command, rawPath string, options repoAddOptions) error {
+	directory, err := a.workingDirectory()
+	if err != nil {
+		return err
+	}
+	configPath, cat, err := loadRegistry(cmd)
+	if err != nil {
+		return err
+	}
+	path, err := expandUserPath(rawPath, directory)
+	if err != nil {
+		return err
+	}
+	repository, err := gitx.OpenRepository(path)
+	if err != nil {
+		return err
+	}
+	workdir, err := cleanWorkdir(options.workdir)
+	if err != nil {
+		return err
+	}
+	if options.remote != "" {
+		if err := config.ValidateRemoteName(options.remote); err != nil {
+			return err
+		}
+	}
+	existing := cat.Lookup(repository)
+	name := options.name
+	if name == "" {
+		if existing != nil {
+			return fmt.Errorf("repository is already configured as %s; use --name to add another profile", existing.Name)
+		}
+		name = cat.UniqueName(filepath.Base(repository.MainPath))
+	}
+	if err := config.ValidateRepoName(name); err != nil {
+		return err
+	}
+	if cat.NameConflicts(name, existing) {
+		return fmt.Errorf("repository alias %q already selects another repository", name)
+	}
+	defaultBranch := options.defaultBranch
+	if defaultBranch == "" && existing != nil {
+		defaultBranch = existing.DefaultBranch
+	}
+	if defaultBranch == "" {
+		defaultBranch = gitx.DefaultBranch(repository.MainPath, options.remote)
+	}
+	row := config.NewWorktreeRepo(repository.MainPath, name, defaultBranch)
+	row.Workdir = workdir
+	row.Remote = options.remote
+	if options.setupChanged {
+		row.Setup = config.SetupCommands(options.setup)
+	}
+	if err := config.AddRepoToFile(configPath, row); err != nil {
+		return fmt.Errorf("registering repository: %w", err)
+	}
+	return a.writeRepoOutput(cmd, "added", "", row)
+}
+
+func (a *application) runRepoRemove(cmd *cobra.Command, alias string) error {
+	configPath, _, err := loadRegistry(cmd)
+	if err != nil {
+		return err
+	}
+	removed, err := config.RemoveRepoFromFile(configPath, alias)
+	if err != nil {
+		return err
+	}
+	return a.writeRepoOutput(cmd, "removed", "", removed)
+}
+
+func (a *application) runRepoRename(cmd *cobra.Command, oldName, newName string) error {
+	configPath, cat, err := loadRegistry(cmd)
+	if err != nil {
+		return err
+	}
+	if err := config.ValidateRepoName(newName); err != nil {
+		return err
+	}
+	// An alias whose row no longer resolves has no repository, so any existing
+	// binding of the new name would make it ambiguous once the row is repaired.
+	self, _, _ := cat.FindRepository(oldName)
+	if cat.NameConflicts(newName, self) {
+		return fmt.Errorf("repository alias %q already selects another repository", newName)
+	}
+	renamed, err := config.RenameRepoInFile(configPath, oldName, newName)
+	if err != nil {
+		return err
+	}
+	return a.writeRepoOutput(cmd, "renamed", oldName, renamed)
+}
+
+func (a *application) writeRepoOutput(cmd *cobra.Command, action, previousName string, row config.RepoConfig) error {
+	if a.jsonOutput {
+		return writeJSON(cmd, repoOutput{
+			Version:       1,
+			Action:        action,
+			Name:          row.Name,
+			PreviousName:  previousName,
+			Path:          row.Path,
+			DefaultBranch: row.DefaultBranch,
+			Workdir:       row.Workdir,
+			Remote:        row.Remote,
+			Setup:         setupCommandList(row.Setup),
+		})
+	}
+	style := a.style(cmd.OutOrStdout())
+	name := style.heading(row.Name)
+	if previousName != "" {
+		name = style.muted(previousName+" → ") + name
+	}
+	suffix := ""
+	if action == "removed" {
+		suffix = "  " + style.muted("(worktrees and branches kept)")
+	}
+	_, err := fmt.Fprintf(cmd.OutOrStdout(), "%s %s  %s%s\n", style.info(strings.ToUpper(action[:1])+action[1:]), name, style.muted(row.Path), suffix)
+	return err
+}
+
+// loadRegistry builds the catalog from configuration alone. Repository
+// management must not treat the unregistered current checkout as a binding.
+func loadRegistry(cmd *cobra.Command) (string, *catalog.Catalog, error) {
+	path, err := config.DefaultConfigPath()
+	if err != nil {
+		return "", nil, err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return "", nil, fmt.Errorf("loading config: %w", err)
+	}
+	cat, warnings := catalog.Build(cfg, "")
+	for _, warning := range warnings {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", warning.Error())
+	}
+	return path, cat, nil
+}
+
+func expandUserPath(path, base string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, strings.TrimPrefix(strings.TrimPrefix(path, "~"), "/"))
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+func cleanWorkdir(workdir string) (string, error) {
+	if workdir == "" {
+		return "", nil
+	}
+	if filepath.IsAbs(workdir) {
+		return "", fmt.Errorf("workdir must be relative: %s", workdir)
+	}
+	cleaned := filepath.Clean(workdir)
+	if cleaned == "." {
+		return "", nil
+	}
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("workdir escapes the worktree: %s", workdir)
+	}
+	return filepath.ToSlash(cleaned), nil
+}
+
+func setupCommandList(steps []config.SetupStep) []string {
+	commands := make([]string, 0, len(steps))
+	for _, step := range steps {
+		commands = append(commands, step.Run)
+	}
+	return commands
+}
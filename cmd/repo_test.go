@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"grove/internal/config"
+)
+
+func TestRepoAddRenameAndRemoveEditConfig(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, "", "")
+	workdir := filepath.Join(repoPath, "packages", "agent")
+	if err := os.MkdirAll(workdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "repo", "add", ".", "--setup", "make deps", "--setup", "make codegen")
+	if err != nil {
+		t.Fatalf("repo add error = %v", err)
+	}
+	var added repoOutput
+	if err := json.Unmarshal([]byte(stdout), &added); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if added.Action != "added" || added.Name != filepath.Base(repoPath) || added.Path != canonicalV2Path(t, repoPath) || added.DefaultBranch != "main" || len(added.Setup) != 2 {
+		t.Fatalf("added = %#v", added)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "repo", "add", "."); err == nil || !strings.Contains(err.Error(), "--name") {
+		t.Fatalf("duplicate repo add error = %v, want --name guidance", err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "repo", "add", ".", "--name", "agent", "--workdir", "packages/agent"); err != nil {
+		t.Fatalf("profile add error = %v", err)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "--color=never", "repo", "rename", "agent", "worker")
+	if err != nil {
+		t.Fatalf("repo rename error = %v", err)
+	}
+	if !strings.HasPrefix(stdout, "Renamed agent → worker") {
+		t.Fatalf("rename stdout = %q", stdout)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "repo", "rm", "worker"); err != nil {
+		t.Fatalf("repo rm error = %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Repos) != 1 || cfg.Repos[0].Name != filepath.Base(repoPath) {
+		t.Fatalf("repos = %#v", cfg.Repos)
+	}
+}
+
+func TestRepoRenameRejectsAliasOfAnotherRepository(t *testing.T) {
+	repoPath := initV2Repo(t)
+	otherPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "  - path: "+otherPath+"\n    name: other\n  - path: "+otherPath+"\n    name: other-agent\n    workdir: packages\n")
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	_, _, err := executeV2(root, "repo", "rename", "app", filepath.Base(otherPath))
+	if err == nil || !strings.Contains(err.Error(), "another repository") {
+		t.Fatalf("rename error = %v, want ambiguity refusal", err)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "repo", "rename", "other-agent", filepath.Base(otherPath)); err != nil {
+		t.Fatalf("renaming a profile to its own repository name error = %v", err)
+	}
+}
+
+func TestScanRegistersDiscoveredRepositoriesInOneWrite(t *testing.T) {
+	workspace := t.TempDir()
+	configured := filepath.Join(workspace, "code", "app")
+	found := filepath.Join(workspace, "code", "tools", "cli")
+	for _, path := range []string{configured, found, filepath.Join(workspace, "a", "b", "c", "too-deep")} {
+		initV2RepoAt(t, path)
+	}
+	writeV2Config(t, configured, "")
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return workspace, nil }})
+	stdout, _, err := executeV2(root, "--json", "scan", ".", "--dry-run")
+	if err != nil {
+		t.Fatalf("scan --dry-run error = %v", err)
+	}
+	var output scanOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.WouldRegister) != 1 || output.WouldRegister[0].Name != "cli" || output.WouldRegister[0].Path != canonicalV2Path(t, found) ||
+		len(output.Known) != 1 || output.Known[0].Name != "app" || len(output.Registered) != 0 {
+		t.Fatalf("scan --dry-run = %#v", output)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return workspace, nil }})
+	stdout, _, err = executeV2(root, "--color=never", "scan", workspace)
+	if err != nil || !strings.Contains(stdout, "Registered 1 repository") {
+		t.Fatalf("scan = %v, stdout %q", err, stdout)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Repos) != 2 || cfg.Repos[1].Name != "cli" || cfg.Repos[1].DefaultBranch != "main" {
+		t.Fatalf("config repos = %#v", cfg.Repos)
+	}
+}
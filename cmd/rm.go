@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"grove/internal/catalog"
 	"grove/internal/inventory"
 	"grove/internal/picker"
 
@@ -24,13 +25,35 @@ type removeOutput struct {
 type removeResult struct {
 	Selector string `json:"selector"`
 	Path     string `json:"path"`
+	// Rule names the --merged detection rule that matched.
+	Rule string `json:"rule,omitempty"`
+	// Branch is empty for detached worktrees. BranchStatus is deleted, kept,
+	// or refused; BranchReason explains a refusal or what proved the merge.
+	Branch       string `json:"branch,omitempty"`
+	BranchStatus string `json:"branch_status,omitempty"`
+	BranchReason string `json:"branch_reason,omitempty"`
+	// Trash is the ref holding a snapshot of a discarded worktree.
+	Trash string `json:"trash,omitempty"`
+	// Hooks reports the pre_remove and post_remove steps that ran.
+	Hooks []setupStepResult `json:"hooks,omitempty"`
 }
 
 type removeCandidate struct {
 	entry *inventory.Entry
 	age   time.Duration
+	rule  string
 }
 
+// Merge detection modes for --merged. Squash adds patch-id comparisons, which
+// cost a few Git processes per unmerged branch.
+const (
+	mergedAncestry = "ancestry"
+	mergedSquash   = "squash"
+)
+
+// mergedStackParent labels branches folded into their recorded stack parent.
+const mergedStackParent = "stack-parent"
+
 type cleanupAge struct {
 	duration time.Duration
 	label    string
@@ -44,19 +67,24 @@ type cleanupSkips struct {
 	detached   int
 	unsafe     int
 	unknownAge int
+	checkedOut int
 }
 
 type removeOptions struct {
 	discard   bool
-	merged    bool
+	trash     bool
+	merged    string
 	missing   bool
 	dryRun    bool
+	fetch     bool
+	branches  bool
+	fuzzy     bool
 	olderThan cleanupAge
 }
 
 func (a *application) removeCommand() *cobra.Command {
-	var discard, merged, missing, dryRun bool
-	var olderThanValue string
+	var discard, noTrash, missing, dryRun, fetch, deleteBranch, fuzzy bool
+	var merged, olderThanValue string
 	command := &cobra.Command{
 		Use:   "rm [selector...]",
 		Short: "Remove worktrees",
@@ -66,8 +94,12 @@ func (a *application) removeCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			merged, err = parseMergedMode(merged)
+			if err != nil {
+				return err
+			}
 			bulkModes := 0
-			if merged {
+			if merged != "" {
 				bulkModes++
 			}
 			if olderThan.duration != 0 {
@@ -85,26 +117,44 @@ func (a *application) removeCommand() *cobra.Command {
 			if bulkModes != 0 && len(args) != 0 {
 				return fmt.Errorf("bulk removal does not accept selectors")
 			}
-			if discard && (merged || missing) {
+			if fetch && merged == "" {
+				return fmt.Errorf("--fetch can only be used with --merged")
+			}
+			if discard && (merged != "" || missing) {
 				return fmt.Errorf("--discard can only be used with selectors or --older-than")
 			}
+			if noTrash && !discard {
+				return fmt.Errorf("--no-trash can only be used with --discard")
+			}
+			if fuzzy && bulkModes != 0 {
+				return fmt.Errorf("--fuzzy can only be used with selectors")
+			}
 			if bulkModes != 0 && a.nullOutput {
 				return fmt.Errorf("--null is only valid for single-worktree removal")
 			}
 			return a.runRemove(cmd, args, removeOptions{
 				discard:   discard,
+				trash:     !noTrash,
 				merged:    merged,
 				missing:   missing,
 				dryRun:    dryRun,
+				fetch:     fetch,
+				branches:  deleteBranch,
+				fuzzy:     fuzzy,
 				olderThan: olderThan,
 			})
 		},
 	}
 	command.Flags().BoolVar(&discard, "discard", false, "Discard all contents, including uncommitted files and unregistered nested repositories")
-	command.Flags().BoolVar(&merged, "merged", false, "Remove all clean worktrees merged into their default branches")
+	command.Flags().BoolVar(&noTrash, "no-trash", false, "With --discard, skip the snapshot that grove restore can bring back")
+	command.Flags().StringVar(&merged, "merged", "", "Remove all clean worktrees merged into their default branches; =squash also detects squash and rebase merges")
+	command.Flags().Lookup("merged").NoOptDefVal = mergedAncestry
 	command.Flags().BoolVar(&missing, "missing", false, "Prune worktree registrations whose directories are gone")
 	command.Flags().StringVar(&olderThanValue, "older-than", "", "Remove worktrees older than a duration such as 14d or 4w")
 	command.Flags().BoolVar(&dryRun, "dry-run", false, "Preview bulk removal without removing anything")
+	command.Flags().BoolVar(&fetch, "fetch", false, "Fetch every repository's remote before checking --merged")
+	command.Flags().BoolVar(&fuzzy, "fuzzy", false, "Match selectors loosely, as if each were written ~selector")
+	command.Flags().BoolVar(&deleteBranch, "delete-branch", false, "Also delete each removed worktree's branch when it is merged; with --discard, even when it is not")
 	return command
 }
 
@@ -113,26 +163,33 @@ func (a *application) runRemove(cmd *cobra.Command, args []string, options remov
 	if err != nil {
 		return err
 	}
-	if options.merged {
-		return a.removeMerged(cmd, context, options.dryRun)
+	branches := &branchCleanup{
+		enabled: options.branches,
+		force:   options.discard,
+		squash:  options.merged == mergedSquash,
+		fetch:   options.fetch,
+		parents: stackParents{},
+	}
+	if options.merged != "" {
+		return a.removeMerged(cmd, context, branches, options.dryRun, options.fetch)
 	}
 	if options.olderThan.duration != 0 {
-		return a.removeOlderThan(cmd, context, options.olderThan, options.discard, options.dryRun)
+		return a.removeOlderThan(cmd, context, branches, options.olderThan, options.discard, options.trash, options.dryRun)
 	}
 	if options.missing {
-		return a.removeMissing(cmd, context, options.dryRun)
+		return a.removeMissing(cmd, context, branches, options.dryRun)
 	}
 	var entries []*inventory.Entry
 	if len(args) != 0 {
 		for _, selector := range args {
-			entry, resolveErr := context.inventory.Resolve(selector, context.directory)
+			entry, resolveErr := a.resolveSelector(context, selector, options.fuzzy)
 			if resolveErr != nil {
 				return resolveErr
 			}
 			entries = appendUniqueEntry(entries, entry)
 		}
 	} else {
-		entries, err = a.pickWorktreesForRemoval(context)
+		entries, err = a.pickLinkedWorktrees(context, "remove > ", nil)
 	}
 	if err != nil {
 		return err
@@ -149,12 +206,20 @@ func (a *application) runRemove(cmd *cobra.Command, args []string, options remov
 			return fmt.Errorf("%s: %w", entry.Selector(), err)
 		}
 	}
+	hooks, err := runPreRemoveHooks(cmd, entries)
+	if err != nil {
+		return err
+	}
 	removed := make([]removeResult, 0, len(entries))
 	for _, entry := range entries {
-		if err := entry.Repository.Git.RemoveWorktree(entry.Worktree.Path, options.discard); err != nil {
+		trash, err := removeWorktree(entry, options.discard, options.trash)
+		if err != nil {
 			return fmt.Errorf("%s: %w", entry.Selector(), err)
 		}
-		removed = append(removed, removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path})
+		result := removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path, Trash: trash}
+		branches.apply(entry, &result)
+		result.Hooks = append(hooks[entry.Worktree.Path], runPostRemoveHook(cmd, entry)...)
+		removed = append(removed, result)
 	}
 	if a.jsonOutput {
 		return writeJSON(cmd, removeOutput{
@@ -164,9 +229,24 @@ func (a *application) runRemove(cmd *cobra.Command, args []string, options remov
 			ReturnPath:  returnPath,
 		})
 	}
+	a.writeRemoveNotices(cmd, removed, false)
 	return a.writePath(cmd, returnPath)
 }
 
+func parseMergedMode(value string) (string, error) {
+	// --merged was a boolean flag; keep its explicit spellings working.
+	switch value {
+	case "true":
+		return mergedAncestry, nil
+	case "false":
+		return "", nil
+	case "", mergedAncestry, mergedSquash:
+		return value, nil
+	default:
+		return "", fmt.Errorf("invalid --merged %q; use --merged or --merged=squash", value)
+	}
+}
+
 func parseOlderThan(value string) (cleanupAge, error) {
 	if value == "" {
 		return cleanupAge{}, nil
@@ -214,14 +294,16 @@ func removeReturnPath(context *commandContext, entries []*inventory.Entry) strin
 	return current.Worktree.Path
 }
 
-func (a *application) pickWorktreesForRemoval(context *commandContext) ([]*inventory.Entry, error) {
+// pickLinkedWorktrees opens the multi-select picker over linked worktrees that
+// include accepts; a nil include offers all of them.
+func (a *application) pickLinkedWorktrees(context *commandContext, prompt string, include func(*inventory.Entry) bool) ([]*inventory.Entry, error) {
 	if a.noInput || !a.dependencies.interactive() {
 		return nil, fmt.Errorf("selector is required in non-interactive mode")
 	}
 	items := make([]picker.Item, 0, len(context.inventory.Entries))
 	now := time.Now()
 	for _, entry := range context.inventory.Entries {
-		if entry.Worktree.Main || entry.Worktree.Prunable {
+		if entry.Worktree.Main || entry.Worktree.Prunable || (include != nil && !include(entry)) {
 			continue
 		}
 		label := entry.Selector()
@@ -233,7 +315,7 @@ func (a *application) pickWorktreesForRemoval(context *commandContext) ([]*inven
 		}
 		items = append(items, picker.Item{Key: entry.Worktree.Path, Label: label})
 	}
-	paths, err := a.dependencies.pickMany("remove > ", items)
+	paths, err := a.dependencies.pickMany(prompt, items, true)
 	if err != nil {
 		return nil, err
 	}
@@ -257,6 +339,26 @@ func appendUniqueEntry(entries []*inventory.Entry, candidate *inventory.Entry) [
 	return append(entries, candidate)
 }
 
+// removeWorktree removes one worktree. With discard and trash, a worktree
+// with changes is first snapshotted; a clean one has nothing to restore. When
+// Git cannot read its status, the snapshot is still attempted.
+func removeWorktree(entry *inventory.Entry, discard, trash bool) (string, error) {
+	ref := ""
+	if discard && trash && worktreeMayHaveChanges(entry) {
+		snapshot, err := entry.Repository.Git.TrashWorktree(entry.Worktree.Path, entry.Worktree.Branch, time.Now())
+		if err != nil {
+			return "", fmt.Errorf("%w; use --no-trash to remove it without a snapshot", err)
+		}
+		ref = snapshot.Ref
+	}
+	return ref, entry.Repository.Git.RemoveWorktree(entry.Worktree.Path, discard)
+}
+
+func worktreeMayHaveChanges(entry *inventory.Entry) bool {
+	dirty, err := entry.Repository.Git.Dirty(entry.Worktree.Path)
+	return err != nil || dirty
+}
+
 func validateRemoveEntry(inv *inventory.Inventory, entry *inventory.Entry, discard bool) error {
 	if entry.Worktree.Main {
 		return fmt.Errorf("refusing to remove the main worktree")
@@ -283,7 +385,7 @@ func validateRemoveEntry(inv *inventory.Inventory, entry *inventory.Entry, disca
 	return nil
 }
 
-func (a *application) removeMissing(cmd *cobra.Command, context *commandContext, dryRun bool) error {
+func (a *application) removeMissing(cmd *cobra.Command, context *commandContext, branches *branchCleanup, dryRun bool) error {
 	var candidates []removeCandidate
 	var skips cleanupSkips
 	for _, entry := range context.inventory.Entries {
@@ -300,12 +402,18 @@ func (a *application) removeMissing(cmd *cobra.Command, context *commandContext,
 		candidates = append(candidates, removeCandidate{entry: entry})
 	}
 
+	hooks, err := preRemoveCandidates(cmd, candidates, dryRun)
+	if err != nil {
+		return err
+	}
 	results := make([]removeResult, 0, len(candidates))
 	var failures []error
 	if dryRun {
 		for _, candidate := range candidates {
 			entry := candidate.entry
-			results = append(results, removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path})
+			result := removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path}
+			branches.preview(entry, &result)
+			results = append(results, result)
 		}
 	} else {
 		pruned := make(map[string]bool)
@@ -326,7 +434,10 @@ func (a *application) removeMissing(cmd *cobra.Command, context *commandContext,
 		for _, candidate := range candidates {
 			entry := candidate.entry
 			if pruned[entry.Repository.Git.MainPath] {
-				results = append(results, removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path})
+				result := removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path}
+				branches.apply(entry, &result)
+				result.Hooks = append(hooks[entry.Worktree.Path], runPostRemoveHook(cmd, entry)...)
+				results = append(results, result)
 			}
 		}
 	}
@@ -353,11 +464,12 @@ func (a *application) removeMissing(cmd *cobra.Command, context *commandContext,
 			action = "Would prune"
 		}
 		style := a.style(cmd.OutOrStdout())
-		fmt.Fprintf(cmd.OutOrStdout(), "%s %s %s\n", style.info(action), worktreeCount(len(results), "missing worktree registration", "missing worktree registrations"), style.muted("(branches kept):"))
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s %s\n", style.info(action), worktreeCount(len(results), "missing worktree registration", "missing worktree registrations"), style.muted(branches.heading()))
 		for _, result := range results {
 			fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", style.branch(result.Selector))
 		}
 	}
+	a.writeRemoveNotices(cmd, results, dryRun)
 	a.writeCleanupSkips(cmd, skips)
 	if len(failures) != 0 {
 		return errors.Join(failures...)
@@ -365,7 +477,7 @@ func (a *application) removeMissing(cmd *cobra.Command, context *commandContext,
 	return nil
 }
 
-func (a *application) removeOlderThan(cmd *cobra.Command, context *commandContext, threshold cleanupAge, discard, dryRun bool) error {
+func (a *application) removeOlderThan(cmd *cobra.Command, context *commandContext, branches *branchCleanup, threshold cleanupAge, discard, trash, dryRun bool) error {
 	currentPath := ""
 	if current, err := context.inventory.Resolve(".", context.directory); err == nil {
 		currentPath = current.Worktree.Path
@@ -429,6 +541,10 @@ func (a *application) removeOlderThan(cmd *cobra.Command, context *commandContex
 		}
 		candidates = append(candidates, removeCandidate{entry: entry, age: age})
 	}
+	hooks, err := preRemoveCandidates(cmd, candidates, dryRun)
+	if err != nil {
+		return err
+	}
 
 	results := make([]removeResult, 0, len(candidates))
 	ages := make(map[string]time.Duration, len(candidates))
@@ -437,6 +553,7 @@ func (a *application) removeOlderThan(cmd *cobra.Command, context *commandContex
 		entry := candidate.entry
 		if dryRun {
 			result := removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path}
+			branches.preview(entry, &result)
 			results = append(results, result)
 			ages[result.Path] = candidate.age
 			continue
@@ -452,11 +569,14 @@ func (a *application) removeOlderThan(cmd *cobra.Command, context *commandContex
 				continue
 			}
 		}
-		if err := entry.Repository.Git.RemoveWorktree(entry.Worktree.Path, discard); err != nil {
+		trashRef, err := removeWorktree(entry, discard, trash)
+		if err != nil {
 			failures = append(failures, fmt.Errorf("%s: %w", entry.Selector(), err))
 			continue
 		}
-		result := removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path}
+		result := removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path, Trash: trashRef}
+		branches.apply(entry, &result)
+		result.Hooks = append(hooks[entry.Worktree.Path], runPostRemoveHook(cmd, entry)...)
 		results = append(results, result)
 		ages[result.Path] = candidate.age
 	}
@@ -483,12 +603,13 @@ func (a *application) removeOlderThan(cmd *cobra.Command, context *commandContex
 			action = "Would remove"
 		}
 		style := a.style(cmd.OutOrStdout())
-		fmt.Fprintf(cmd.OutOrStdout(), "%s %s older than %s %s\n", style.info(action), worktreeCount(len(results), "worktree", "worktrees"), threshold.label, style.muted("(branches kept):"))
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s older than %s %s\n", style.info(action), worktreeCount(len(results), "worktree", "worktrees"), threshold.label, style.muted(branches.heading()))
 		for _, result := range results {
 			age := "created " + relativeAge(ages[result.Path]) + " ago"
 			fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s\n", style.branch(result.Selector), style.muted(age))
 		}
 	}
+	a.writeRemoveNotices(cmd, results, dryRun)
 	a.writeCleanupSkips(cmd, skips)
 	if len(failures) != 0 {
 		return errors.Join(failures...)
@@ -496,6 +617,19 @@ func (a *application) removeOlderThan(cmd *cobra.Command, context *commandContex
 	return nil
 }
 
+// preRemoveCandidates runs pre_remove for every bulk candidate before any
+// removal; a dry run previews without running hooks.
+func preRemoveCandidates(cmd *cobra.Command, candidates []removeCandidate, dryRun bool) (map[string][]setupStepResult, error) {
+	if dryRun {
+		return nil, nil
+	}
+	entries := make([]*inventory.Entry, 0, len(candidates))
+	for _, candidate := range candidates {
+		entries = append(entries, candidate.entry)
+	}
+	return runPreRemoveHooks(cmd, entries)
+}
+
 func worktreeCount(count int, singular, plural string) string {
 	if count == 1 {
 		return fmt.Sprintf("1 %s", singular)
@@ -505,7 +639,7 @@ func worktreeCount(count int, singular, plural string) string {
 
 func (a *application) writeCleanupSkips(cmd *cobra.Command, skips cleanupSkips) {
 	style := a.style(cmd.ErrOrStderr())
-	parts := make([]string, 0, 7)
+	parts := make([]string, 0, 8)
 	if skips.dirty != 0 {
 		parts = append(parts, fmt.Sprintf("%d dirty %s", skips.dirty, style.muted("(use --discard)")))
 	}
@@ -524,6 +658,9 @@ func (a *application) writeCleanupSkips(cmd *cobra.Command, skips cleanupSkips)
 	if skips.unsafe != 0 {
 		parts = append(parts, fmt.Sprintf("%d unsafe", skips.unsafe))
 	}
+	if skips.checkedOut != 0 {
+		parts = append(parts, fmt.Sprintf("%d checked out", skips.checkedOut))
+	}
 	if skips.unknownAge != 0 {
 		parts = append(parts, fmt.Sprintf("%d with unknown age", skips.unknownAge))
 	}
@@ -532,12 +669,21 @@ func (a *application) writeCleanupSkips(cmd *cobra.Command, skips cleanupSkips)
 	}
 }
 
-func (a *application) removeMerged(cmd *cobra.Command, context *commandContext, dryRun bool) error {
+func (a *application) removeMerged(cmd *cobra.Command, context *commandContext, branches *branchCleanup, dryRun, fetch bool) error {
+	repositories := make([]*catalog.Repository, 0, len(context.catalog.Repositories))
+	for _, repository := range context.catalog.Repositories {
+		if context.catalog.Current == repository && !context.catalog.CurrentRegistered {
+			continue
+		}
+		repositories = append(repositories, repository)
+	}
+	fetchRepositories(cmd, repositories, fetch)
 	currentPath := ""
 	if current, err := context.inventory.Resolve(".", context.directory); err == nil {
 		currentPath = current.Worktree.Path
 	}
 	var candidates []removeCandidate
+	parents, squash := branches.parents, branches.squash
 	for _, entry := range context.inventory.Entries {
 		if context.catalog.Current == entry.Repository && !context.catalog.CurrentRegistered {
 			continue
@@ -557,15 +703,19 @@ func (a *application) removeMerged(cmd *cobra.Command, context *commandContext,
 		if dirty {
 			continue
 		}
-		merged, _, err := entry.Repository.Git.BranchMerged(entry.Worktree.Branch, entry.Repository.DefaultBranch)
+		rule, err := parents.merged(entry, squash, fetch)
 		if err != nil {
 			fmt.Fprintf(cmd.ErrOrStderr(), "warning: skipping %s: %v\n", entry.Selector(), err)
 			continue
 		}
-		if !merged {
+		if rule == "" {
 			continue
 		}
-		candidates = append(candidates, removeCandidate{entry: entry})
+		candidates = append(candidates, removeCandidate{entry: entry, rule: rule})
+	}
+	hooks, err := preRemoveCandidates(cmd, candidates, dryRun)
+	if err != nil {
+		return err
 	}
 
 	results := make([]removeResult, 0, len(candidates))
@@ -573,7 +723,9 @@ func (a *application) removeMerged(cmd *cobra.Command, context *commandContext,
 	for _, candidate := range candidates {
 		entry := candidate.entry
 		if dryRun {
-			results = append(results, removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path})
+			result := removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path, Rule: candidate.rule}
+			branches.preview(entry, &result)
+			results = append(results, result)
 			continue
 		}
 		dirty, err := entry.Repository.Git.Dirty(entry.Worktree.Path)
@@ -581,19 +733,22 @@ func (a *application) removeMerged(cmd *cobra.Command, context *commandContext,
 			failures = append(failures, fmt.Errorf("%s: %w", entry.Selector(), err))
 			continue
 		}
-		merged, _, mergeErr := entry.Repository.Git.BranchMerged(entry.Worktree.Branch, entry.Repository.DefaultBranch)
+		rule, mergeErr := parents.merged(entry, squash, fetch)
 		if mergeErr != nil {
 			failures = append(failures, fmt.Errorf("%s: %w", entry.Selector(), mergeErr))
 			continue
 		}
-		if dirty || !merged {
+		if dirty || rule == "" {
 			continue
 		}
 		if err := entry.Repository.Git.RemoveWorktree(entry.Worktree.Path, false); err != nil {
 			failures = append(failures, fmt.Errorf("%s: %w", entry.Selector(), err))
 			continue
 		}
-		results = append(results, removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path})
+		result := removeResult{Selector: entry.Selector(), Path: entry.Worktree.Path, Rule: rule}
+		branches.apply(entry, &result)
+		result.Hooks = append(hooks[entry.Worktree.Path], runPostRemoveHook(cmd, entry)...)
+		results = append(results, result)
 	}
 
 	if a.jsonOutput {
@@ -618,15 +773,54 @@ func (a *application) removeMerged(cmd *cobra.Command, context *commandContext,
 			verb = "would remove"
 		}
 		for _, result := range results {
-			fmt.Fprintf(cmd.OutOrStdout(), "%s %s  %s\n", verb, result.Selector, result.Path)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s  %s  (%s)\n", verb, result.Selector, result.Path, result.Rule)
 		}
 	}
+	a.writeRemoveNotices(cmd, results, dryRun)
 	if len(failures) != 0 {
 		return errors.Join(failures...)
 	}
 	return nil
 }
 
+// stackParents caches each repository's recorded stack parents for one bulk
+// cleanup run.
+type stackParents map[*catalog.Repository]map[string]string
+
+// merged names the rule under which a branch counts as merged: its tip is in
+// the default branch, its changes landed there as a squash or rebase when
+// squash is set, or a stacked branch is already in its recorded parent. Either
+// way the worktree holds no commits that would be lost with it. An empty rule
+// means the branch is not merged. The default branch's remote-tracking ref
+// only counts when fetch is set or the repository is configured to fetch.
+func (p stackParents) merged(entry *inventory.Entry, squash, fetch bool) (string, error) {
+	return p.mergedBranch(entry.Repository, entry.Worktree.Branch, squash, fetch)
+}
+
+func (p stackParents) mergedBranch(repository *catalog.Repository, branch string, squash, fetch bool) (string, error) {
+	rule, err := repository.Git.BranchMergeRule(branch, repository.DefaultBranch, squash, fetch || repository.Fetch)
+	if err != nil || rule != "" {
+		return string(rule), err
+	}
+	parents, ok := p[repository]
+	if !ok {
+		parents, err = repository.Git.BranchParents()
+		if err != nil {
+			return "", err
+		}
+		p[repository] = parents
+	}
+	parent := parents[branch]
+	if parent == "" || !repository.Git.RefExists("refs/heads/"+parent) {
+		return "", nil
+	}
+	contained, err := repository.Git.BranchContainedIn(branch, parent)
+	if err != nil || !contained {
+		return "", err
+	}
+	return mergedStackParent, nil
+}
+
 func mergedSkipReason(entry *inventory.Entry, currentPath string) string {
 	switch {
 	case entry.Worktree.Main:
@@ -651,3 +845,92 @@ func lockedError(entry *inventory.Entry) error {
 	}
 	return fmt.Errorf("worktree is locked: %s", reason)
 }
+
+// Outcomes for a removed worktree's branch.
+const (
+	branchDeleted = "deleted"
+	branchKept    = "kept"
+	branchRefused = "refused"
+)
+
+// branchCleanup decides what --delete-branch does with each removed worktree's
+// branch. A branch proven merged is deleted; an unmerged one only with force.
+type branchCleanup struct {
+	enabled bool
+	force   bool
+	squash  bool
+	fetch   bool
+	parents stackParents
+}
+
+func (c *branchCleanup) plan(entry *inventory.Entry) (string, string) {
+	if !c.enabled {
+		return branchKept, ""
+	}
+	rule, err := c.parents.merged(entry, c.squash, c.fetch)
+	if err != nil {
+		return branchRefused, err.Error()
+	}
+	if rule != "" {
+		return branchDeleted, "merged (" + rule + ")"
+	}
+	if c.force {
+		return branchDeleted, "not merged; deleted with --discard"
+	}
+	return branchRefused, "not merged; use --discard to delete it"
+}
+
+// preview fills in the planned branch outcome without deleting anything.
+func (c *branchCleanup) preview(entry *inventory.Entry, result *removeResult) {
+	if entry.Worktree.Branch == "" {
+		return
+	}
+	result.Branch = entry.Worktree.Branch
+	result.BranchStatus, result.BranchReason = c.plan(entry)
+}
+
+// apply runs after the worktree is gone, so Git no longer refuses to delete
+// a branch that is checked out there.
+func (c *branchCleanup) apply(entry *inventory.Entry, result *removeResult) {
+	c.preview(entry, result)
+	if result.BranchStatus != branchDeleted {
+		return
+	}
+	if err := entry.Repository.Git.DeleteBranch(entry.Worktree.Branch); err != nil {
+		result.BranchStatus, result.BranchReason = branchRefused, err.Error()
+	}
+}
+
+func (c *branchCleanup) heading() string {
+	switch {
+	case c.enabled && c.force:
+		return "(branches deleted):"
+	case c.enabled:
+		return "(merged branches deleted):"
+	default:
+		return "(branches kept):"
+	}
+}
+
+func (a *application) writeRemoveNotices(cmd *cobra.Command, results []removeResult, dryRun bool) {
+	if a.jsonOutput {
+		return
+	}
+	for _, result := range results {
+		if result.Trash != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "saved %s to trash; undo with: grove restore %s\n", result.Selector, result.Selector)
+		}
+	}
+	deleted := "deleted"
+	if dryRun {
+		deleted = "would delete"
+	}
+	for _, result := range results {
+		switch result.BranchStatus {
+		case branchDeleted:
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s branch %s: %s\n", deleted, result.Branch, result.BranchReason)
+		case branchRefused:
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: keeping branch %s: %s\n", result.Branch, result.BranchReason)
+		}
+	}
+}
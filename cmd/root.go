@@ -17,10 +17,12 @@ var Version = "dev"
 type commandDependencies struct {
 	getwd       func() (string, error)
 	interactive func() bool
-	pick        func(string, []picker.Item) (string, error)
-	pickMany    func(string, []picker.Item) ([]string, error)
+	pick        func(string, []picker.Item, bool, ...picker.Action) (picker.Selection, error)
+	pickMany    func(string, []picker.Item, bool) ([]string, error)
 	lastVisited func(string) (time.Time, bool)
 	markVisited func(string) error
+	moveVisited func(from, to string) error
+	recent      func() ([]recency.Visit, error)
 }
 
 type application struct {
@@ -45,7 +47,7 @@ func newRootCommand(dependencies commandDependencies) *cobra.Command {
 	if dependencies.pickMany == nil {
 		dependencies.pickMany = picker.SelectMany
 	}
-	if dependencies.lastVisited == nil || dependencies.markVisited == nil {
+	if dependencies.lastVisited == nil || dependencies.markVisited == nil || dependencies.moveVisited == nil || dependencies.recent == nil {
 		// Resolve the state root once per command tree. Reads degrade to an
 		// unranked item, while writes surface through the non-fatal warning at the
 		// navigation handoff; optional history never blocks unrelated commands.
@@ -66,6 +68,22 @@ func newRootCommand(dependencies commandDependencies) *cobra.Command {
 				return tracker.MarkVisited(path)
 			}
 		}
+		if dependencies.moveVisited == nil {
+			dependencies.moveVisited = func(from, to string) error {
+				if trackerErr != nil {
+					return trackerErr
+				}
+				return tracker.Move(from, to)
+			}
+		}
+		if dependencies.recent == nil {
+			dependencies.recent = func() ([]recency.Visit, error) {
+				if trackerErr != nil {
+					return nil, trackerErr
+				}
+				return tracker.Recent(0)
+			}
+		}
 	}
 	app := &application{dependencies: dependencies}
 	root := &cobra.Command{
@@ -87,7 +105,7 @@ func newRootCommand(dependencies commandDependencies) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return app.runNavigate(cmd, args)
+			return app.runNavigate(cmd, args, false)
 		},
 	}
 	root.SetHelpFunc(app.writeHelp)
@@ -97,11 +115,22 @@ func newRootCommand(dependencies commandDependencies) *cobra.Command {
 	root.PersistentFlags().BoolVarP(&app.nullOutput, "null", "0", false, "Terminate path output with NUL")
 	root.PersistentFlags().StringVar(&app.colorMode, "color", "auto", "Color output: auto, always, or never")
 	root.AddCommand(
+		app.adoptCommand(),
+		app.branchesCommand(),
 		app.cdCommand(),
 		app.configCommand(),
+		app.doctorCommand(),
 		app.listCommand(),
+		app.lockCommand(),
+		app.moveCommand(),
 		app.newCommand(),
+		app.previewCommand(),
 		app.removeCommand(),
+		app.repoCommand(),
+		app.restoreCommand(),
+		app.scanCommand(),
+		app.trashCommand(),
+		app.unlockCommand(),
 	)
 	return root
 }
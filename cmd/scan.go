@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"grove/internal/config"
+	gitx "grove/internal/git"
+	"grove/internal/picker"
+
+	"github.com/spf13/cobra"
+)
+
+type scanOutput struct {
+	Version       int              `json:"version"`
+	DryRun        bool             `json:"dry_run"`
+	Registered    []scanRepository `json:"registered"`
+	WouldRegister []scanRepository `json:"would_register"`
+	// Known lists repositories found that were already configured.
+	Known []scanRepository `json:"known"`
+}
+
+type scanRepository struct {
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+}
+
+// defaultScanDepth reaches ~/code/<org>/<repo> from ~ without walking a
+// whole home directory.
+const defaultScanDepth = 3
+
+func (a *application) scanCommand() *cobra.Command {
+	var depth int
+	var dryRun bool
+	command := &cobra.Command{
+		Use:   "scan <dir>",
+		Short: "Find Git repositories under a directory and register them",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if depth < 0 {
+				return fmt.Errorf("--depth must not be negative")
+			}
+			return a.runScan(cmd, args[0], depth, dryRun)
+		},
+	}
+	command.Flags().IntVar(&depth, "depth", defaultScanDepth, "Directory levels below <dir> to search")
+	command.Flags().BoolVar(&dryRun, "dry-run", false, "List repositories that would be registered without changing the config")
+	return command
+}
+
+func (a *application) runScan(cmd *cobra.Command, rawPath string, depth int, dryRun bool) error {
+	directory, err := a.workingDirectory()
+	if err != nil {
+		return err
+	}
+	configPath, cat, err := loadRegistry(cmd)
+	if err != nil {
+		return err
+	}
+	root, err := expandUserPath(rawPath, directory)
+	if err != nil {
+		return err
+	}
+	repositories, err := gitx.DiscoverRepositories(root, depth)
+	if err != nil {
+		return err
+	}
+
+	known := make([]scanRepository, 0)
+	candidates := make([]config.RepoConfig, 0, len(repositories))
+	names := make(map[string]bool)
+	for _, repository := range repositories {
+		if existing := cat.Lookup(repository); existing != nil {
+			known = append(known, scanRepository{Name: existing.Name, Path: repository.MainPath, DefaultBranch: existing.DefaultBranch})
+			continue
+		}
+		base := filepath.Base(repository.MainPath)
+		name := cat.UniqueName(base)
+		for suffix := 2; names[name] || cat.NameConflicts(name, nil); suffix++ {
+			name = cat.UniqueName(fmt.Sprintf("%s-%d", base, suffix))
+		}
+		names[name] = true
+		candidates = append(candidates, config.NewWorktreeRepo(repository.MainPath, name, gitx.DefaultBranch(repository.MainPath, gitx.DefaultRemote)))
+	}
+
+	if !dryRun && len(candidates) != 0 && !a.noInput && a.dependencies.interactive() {
+		candidates, err = a.pickScanCandidates(candidates)
+		if err != nil {
+			return err
+		}
+	}
+	if !dryRun && len(candidates) != 0 {
+		if err := config.AddReposToFile(configPath, candidates); err != nil {
+			return fmt.Errorf("registering repositories: %w", err)
+		}
+	}
+
+	results := make([]scanRepository, 0, len(candidates))
+	for _, candidate := range candidates {
+		results = append(results, scanRepository{Name: candidate.Name, Path: candidate.Path, DefaultBranch: candidate.DefaultBranch})
+	}
+	if a.jsonOutput {
+		output := scanOutput{Version: 1, DryRun: dryRun, Registered: []scanRepository{}, WouldRegister: []scanRepository{}, Known: known}
+		if dryRun {
+			output.WouldRegister = results
+		} else {
+			output.Registered = results
+		}
+		return writeJSON(cmd, output)
+	}
+	style := a.style(cmd.OutOrStdout())
+	if len(results) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No new repositories under %s.\n", root)
+	} else {
+		action := "Registered"
+		if dryRun {
+			action = "Would register"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", style.info(action), worktreeCount(len(results), "repository", "repositories"))
+		for _, result := range results {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s\n", style.branch(result.Name), style.muted(result.Path))
+		}
+	}
+	if len(known) != 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s\n", a.style(cmd.ErrOrStderr()).muted(worktreeCount(len(known), "repository", "repositories")+" already configured"))
+	}
+	return nil
+}
+
+func (a *application) pickScanCandidates(candidates []config.RepoConfig) ([]config.RepoConfig, error) {
+	items := make([]picker.Item, 0, len(candidates))
+	for _, candidate := range candidates {
+		items = append(items, picker.Item{Key: candidate.Path, Label: fmt.Sprintf("%-24s %s", candidate.Name, candidate.Path)})
+	}
+	paths, err := a.dependencies.pickMany("register > ", items, false)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		selected[path] = true
+	}
+	chosen := make([]config.RepoConfig, 0, len(paths))
+	for _, candidate := range candidates {
+		if selected[candidate.Path] {
+			chosen = append(chosen, candidate)
+		}
+	}
+	return chosen, nil
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"grove/internal/catalog"
+	"grove/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// setupStepResult reports one setup or hook step in --json output. ExitCode
+// is absent for steps that did not run and -1 for steps killed by their
+// timeout. Hook names the hook a step belongs to; it is empty for setup.
+type setupStepResult struct {
+	Hook       string `json:"hook,omitempty"`
+	Command    string `json:"command"`
+	Status     string `json:"status"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+const (
+	setupOK       = "ok"
+	setupFailed   = "failed"
+	setupTimedOut = "timed_out"
+	setupSkipped  = "skipped"
+)
+
+// setupTarget is the worktree a profile's steps concern, exported to each
+// step as GROVE_* environment variables. Steps run in the profile's workdir
+// under root, which defaults to the worktree itself.
+type setupTarget struct {
+	repository *catalog.Repository
+	profile    *catalog.Profile
+	branch     string
+	path       string
+	root       string
+}
+
+func (t setupTarget) environment() []string {
+	return append(os.Environ(),
+		"GROVE_REPO="+t.repository.Name,
+		"GROVE_BRANCH="+t.branch,
+		"GROVE_WORKTREE="+t.path,
+		"GROVE_MAIN_PATH="+t.repository.Git.MainPath,
+		"GROVE_PROFILE="+t.profile.Name,
+	)
+}
+
+// runSetup runs the profile's setup steps; callers apply the setup_failure
+// policy to the returned error.
+func runSetup(cmd *cobra.Command, target setupTarget) ([]setupStepResult, error) {
+	if target.profile == nil {
+		return nil, nil
+	}
+	return runSteps(cmd, target, "setup", target.profile.Setup)
+}
+
+// runSteps runs steps in order, streaming their output to stderr and labelling
+// progress with name. A failed step stops the remaining ones unless it
+// continues on error; the returned error describes that failure.
+func runSteps(cmd *cobra.Command, target setupTarget, name string, steps []config.SetupStep) ([]setupStepResult, error) {
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	results := make([]setupStepResult, 0, len(steps))
+	root := target.root
+	if root == "" {
+		root = target.path
+	}
+	directory, err := setupDirectory(root, target.profile.Workdir)
+	if err != nil {
+		for _, step := range steps {
+			results = append(results, setupStepResult{Command: step.Run, Status: setupSkipped, Reason: err.Error()})
+		}
+		return results, fmt.Errorf("%s skipped: %w", name, err)
+	}
+	environment := target.environment()
+	var failure error
+	for _, step := range steps {
+		if failure != nil {
+			results = append(results, setupStepResult{Command: step.Run, Status: setupSkipped, Reason: "an earlier step failed"})
+			continue
+		}
+		if reason := setupConditionUnmet(directory, step.When); reason != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: skipping %s (%s)\n", name, step.Run, reason)
+			results = append(results, setupStepResult{Command: step.Run, Status: setupSkipped, Reason: reason})
+			continue
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s\n", name, step.Run)
+		result, err := runSetupStep(cmd, directory, environment, step)
+		results = append(results, result)
+		if err == nil {
+			continue
+		}
+		if step.ContinueOnError {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s %q failed, continuing: %v\n", name, step.Run, err)
+			continue
+		}
+		failure = fmt.Errorf("%s %q: %w", name, step.Run, err)
+	}
+	return results, failure
+}
+
+func runSetupStep(cmd *cobra.Command, directory string, environment []string, step config.SetupStep) (setupStepResult, error) {
+	result := setupStepResult{Command: step.Run}
+	timeout, err := step.TimeoutDuration()
+	if err != nil {
+		// The catalog rejects such rows; this guards callers building profiles by hand.
+		result.Status = setupFailed
+		return result, err
+	}
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+	process := exec.CommandContext(ctx, "sh", "-c", step.Run)
+	process.Dir = directory
+	process.Env = environment
+	process.Stdout = cmd.ErrOrStderr()
+	process.Stderr = cmd.ErrOrStderr()
+	process.Stdin = os.Stdin
+	if timeout > 0 {
+		// Only a step that can time out leaves the terminal's foreground
+		// group; others must still get Ctrl-C and may read the terminal.
+		killProcessGroupOnCancel(process)
+	}
+	// Children that escape the process group must not hold the output open.
+	process.WaitDelay = time.Second
+	started := time.Now()
+	err = process.Run()
+	result.DurationMS = time.Since(started).Milliseconds()
+	if process.ProcessState != nil {
+		code := process.ProcessState.ExitCode()
+		result.ExitCode = &code
+	}
+	switch {
+	case timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Status = setupTimedOut
+		return result, fmt.Errorf("timed out after %s", timeout)
+	case err != nil:
+		result.Status = setupFailed
+		return result, err
+	}
+	result.Status = setupOK
+	return result, nil
+}
+
+// setupConditionUnmet explains why a step's when condition excludes it, or
+// returns "" when the step should run.
+func setupConditionUnmet(directory string, when config.SetupCondition) string {
+	exists := func(path string) bool {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(directory, path)
+		}
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	if when.Exists != "" && !exists(when.Exists) {
+		return when.Exists + " does not exist"
+	}
+	if when.Missing != "" && exists(when.Missing) {
+		return when.Missing + " exists"
+	}
+	return ""
+}
+
+func setupDirectory(worktreePath, workdir string) (string, error) {
+	root, err := filepath.EvalSymlinks(worktreePath)
+	if err != nil {
+		return "", fmt.Errorf("resolving worktree: %w", err)
+	}
+	if workdir == "" {
+		return root, nil
+	}
+	if filepath.IsAbs(workdir) {
+		return "", fmt.Errorf("workdir must be relative: %s", workdir)
+	}
+	directory := filepath.Clean(filepath.Join(root, workdir))
+	if !pathInside(root, directory) {
+		return "", fmt.Errorf("workdir escapes the worktree: %s", workdir)
+	}
+	info, err := os.Stat(directory)
+	if err != nil {
+		return "", fmt.Errorf("workdir %s: %w", workdir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("workdir is not a directory: %s", workdir)
+	}
+	resolved, err := filepath.EvalSymlinks(directory)
+	if err != nil {
+		return "", fmt.Errorf("resolving workdir %s: %w", workdir, err)
+	}
+	if !pathInside(root, resolved) {
+		return "", fmt.Errorf("workdir escapes the worktree through a symlink: %s", workdir)
+	}
+	return resolved, nil
+}
+
+// pathInside reports whether path is root or lies beneath it, comparing the
+// paths as given; callers resolve symlinks first where that matters.
+func pathInside(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
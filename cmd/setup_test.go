@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSetupStepsReceiveEnvironmentAndReportResults(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, "", strings.Join([]string{
+		"  - path: " + repoPath,
+		"    name: app",
+		"    default_branch: main",
+		"    setup:",
+		"      - printf '%s|%s|%s|%s|%s' \"$GROVE_REPO\" \"$GROVE_BRANCH\" \"$GROVE_WORKTREE\" \"$GROVE_MAIN_PATH\" \"$GROVE_PROFILE\" > env.txt",
+		"      - run: touch skipped-marker",
+		"        when:",
+		"          exists: package.json",
+		"      - run: exit 3",
+		"        continue_on_error: true",
+		"      - run: (sleep 1; touch survivor-marker) & sleep 5",
+		"        timeout: 100ms",
+		"      - touch never-marker",
+		"",
+	}, "\n"))
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, stderr, err := executeV2(root, "--json", "new", "auth")
+	if err != nil {
+		t.Fatalf("new error = %v; stderr=%s", err, stderr)
+	}
+	var output newOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	statuses := make([]string, 0, len(output.Setup))
+	for _, step := range output.Setup {
+		statuses = append(statuses, step.Status)
+	}
+	if got := strings.Join(statuses, ","); got != "ok,skipped,failed,timed_out,skipped" {
+		t.Fatalf("setup statuses = %s; results %#v", got, output.Setup)
+	}
+	if code := output.Setup[2].ExitCode; code == nil || *code != 3 {
+		t.Fatalf("continue_on_error exit code = %v", code)
+	}
+	if output.Setup[1].ExitCode != nil || output.Setup[1].Reason != "package.json does not exist" {
+		t.Fatalf("skipped step = %#v", output.Setup[1])
+	}
+	if !strings.Contains(stderr, "warning: setup \"(sleep 1; touch survivor-marker) & sleep 5\": timed out after 100ms") {
+		t.Fatalf("stderr = %q, want timeout warning", stderr)
+	}
+	data, err := os.ReadFile(filepath.Join(output.Path, "env.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainPath := canonicalV2Path(t, repoPath)
+	if want := strings.Join([]string{"app", "feat/auth", output.Path, mainPath, "app"}, "|"); string(data) != want {
+		t.Fatalf("setup environment = %q, want %q", data, want)
+	}
+	if _, err := os.Stat(filepath.Join(output.Path, "never-marker")); !os.IsNotExist(err) {
+		t.Fatalf("step after a failure ran: %v", err)
+	}
+	// The timeout kills the step's whole process group, not just the shell.
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(output.Path, "survivor-marker")); !os.IsNotExist(err) {
+		t.Fatalf("child of a timed-out step kept running: %v", err)
+	}
+}
+
+func TestNewSetupFailurePolicies(t *testing.T) {
+	for _, policy := range []string{"fail", "remove"} {
+		t.Run(policy, func(t *testing.T) {
+			repoPath := initV2Repo(t)
+			writeV2Config(t, "", strings.Join([]string{
+				"  - path: " + repoPath,
+				"    name: app",
+				"    default_branch: main",
+				"    setup_failure: " + policy,
+				"    setup:",
+				"      - exit 1",
+				"",
+			}, "\n"))
+			root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+			stdout, _, err := executeV2(root, "new", "auth")
+			if err == nil || stdout != "" {
+				t.Fatalf("new = %v, stdout %q; want failure without a path", err, stdout)
+			}
+			wantPath := filepath.Join(canonicalV2Path(t, repoPath), ".wt", "feat", "auth")
+			_, statErr := os.Stat(wantPath)
+			branches := v2GitOutput(t, repoPath, "branch", "--list", "feat/auth")
+			if policy == "fail" && (statErr != nil || branches == "" || !strings.Contains(err.Error(), "worktree kept at")) {
+				t.Fatalf("fail policy: err %v, stat %v, branches %q", err, statErr, branches)
+			}
+			if policy == "remove" && (!os.IsNotExist(statErr) || branches != "" || !strings.Contains(err.Error(), "removed worktree")) {
+				t.Fatalf("remove policy: err %v, stat %v, branches %q", err, statErr, branches)
+			}
+		})
+	}
+}
+
+func TestNewCopiesAndLinksUntrackedFilesFromMainCheckout(t *testing.T) {
+	repoPath := initV2Repo(t)
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(repoPath, "tools")); err != nil {
+		t.Fatal(err)
+	}
+	runV2Git(t, repoPath, "add", "tools")
+	runV2Git(t, repoPath, "commit", "-m", "add tools link")
+	for name, content := range map[string]string{
+		".env":                  "SECRET=1",
+		".env.local":            "LOCAL=1",
+		".vscode/settings.json": "{}",
+		"node_modules/pkg/x.js": "x",
+		"README":                "changed in main",
+		"secret":                "outside",
+	} {
+		path := filepath.Join(repoPath, name)
+		if name == "secret" {
+			path = filepath.Join(outside, name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeV2Config(t, "", strings.Join([]string{
+		"  - path: " + repoPath,
+		"    name: app",
+		"    default_branch: main",
+		"    copy: ['.env*', .vscode, README, tools/secret]",
+		"    link: [node_modules]",
+		"    setup:",
+		"      - test -f .env.local",
+		"",
+	}, "\n"))
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, stderr, err := executeV2(root, "--json", "new", "auth")
+	if err != nil {
+		t.Fatalf("new error = %v; stderr=%s", err, stderr)
+	}
+	var output newOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	actions := make(map[string]string)
+	for _, file := range output.Files {
+		actions[file.Path] = file.Action
+	}
+	want := map[string]string{
+		".env":                  "copied",
+		".env.local":            "copied",
+		".vscode/settings.json": "copied",
+		"README":                "skipped",
+		"tools/secret":          "skipped",
+		"node_modules":          "linked",
+	}
+	for path, action := range want {
+		if actions[path] != action {
+			t.Fatalf("files = %#v, want %s %s", output.Files, path, action)
+		}
+	}
+	if output.Setup[0].Status != setupOK {
+		t.Fatalf("setup ran before files were copied: %#v", output.Setup)
+	}
+	if data, err := os.ReadFile(filepath.Join(output.Path, ".env")); err != nil || string(data) != "SECRET=1" {
+		t.Fatalf("copied .env = %q, %v", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(output.Path, ".env")); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("copied .env mode = %v, %v", info, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(output.Path, "README")); err != nil || string(data) == "changed in main" {
+		t.Fatalf("tracked README was overwritten: %q, %v", data, err)
+	}
+	if target, err := os.Readlink(filepath.Join(output.Path, "node_modules")); err != nil || target != filepath.Join(canonicalV2Path(t, repoPath), "node_modules") {
+		t.Fatalf("node_modules link = %q, %v", target, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(outside, "secret")); err != nil || string(data) != "outside" {
+		t.Fatalf("file outside the worktree changed: %q, %v", data, err)
+	}
+	if !strings.Contains(stderr, "copy: .env\n") || !strings.Contains(stderr, "warning: not copying tools/secret") {
+		t.Fatalf("stderr = %q", stderr)
+	}
+}
+
+func TestNewClonesDirectoriesFromMostRecentlyVisitedWorktree(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, "", strings.Join([]string{
+		"  - path: " + repoPath,
+		"    name: app",
+		"    default_branch: main",
+		"    clone_dirs: [node_modules, .venv]",
+		"    setup:",
+		"      - test \"$(cat node_modules/pkg/index.js)\" = warm",
+		"",
+	}, "\n"))
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	warmPath, _, err := executeV2(root, "new", "warm")
+	if err != nil {
+		t.Fatalf("new warm error = %v", err)
+	}
+	warmPath = strings.TrimSpace(warmPath)
+	for path, content := range map[string]string{
+		filepath.Join(repoPath, "node_modules", "pkg", "index.js"): "stale",
+		filepath.Join(warmPath, "node_modules", "pkg", "index.js"): "warm",
+		filepath.Join(repoPath, ".venv", "bin", "python"):          "venv",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root = newRootCommand(commandDependencies{
+		getwd: func() (string, error) { return repoPath, nil },
+		lastVisited: func(path string) (time.Time, bool) {
+			return time.Now(), path == warmPath
+		},
+	})
+	stdout, stderr, err := executeV2(root, "--json", "new", "auth")
+	if err != nil {
+		t.Fatalf("new error = %v; stderr=%s", err, stderr)
+	}
+	var output newOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.Clones) != 2 || output.Clones[0].Source != warmPath || output.Clones[1].Source != canonicalV2Path(t, repoPath) {
+		t.Fatalf("clones = %#v", output.Clones)
+	}
+	for _, clone := range output.Clones {
+		if clone.Method != "reflink" && clone.Method != "hardlink" {
+			t.Fatalf("clone %s method = %q (%s)", clone.Path, clone.Method, clone.Reason)
+		}
+	}
+	if len(output.Setup) != 1 || output.Setup[0].Status != setupOK {
+		t.Fatalf("setup did not see the cloned directory: %#v; stderr=%s", output.Setup, stderr)
+	}
+	if data, err := os.ReadFile(filepath.Join(output.Path, ".venv", "bin", "python")); err != nil || string(data) != "venv" {
+		t.Fatalf("cloned .venv = %q, %v", data, err)
+	}
+}
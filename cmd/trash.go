@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"grove/internal/catalog"
+	gitx "grove/internal/git"
+	"grove/internal/inventory"
+	"grove/internal/picker"
+
+	"github.com/spf13/cobra"
+)
+
+type trashListOutput struct {
+	Version int          `json:"version"`
+	Entries []trashEntry `json:"entries"`
+}
+
+type trashPurgeOutput struct {
+	Version    int          `json:"version"`
+	DryRun     bool         `json:"dry_run"`
+	Purged     []trashEntry `json:"purged"`
+	WouldPurge []trashEntry `json:"would_purge"`
+}
+
+type restoreOutput struct {
+	Version    int    `json:"version"`
+	Repository string `json:"repository"`
+	Branch     string `json:"branch"`
+	Path       string `json:"path"`
+	Trash      string `json:"trash"`
+}
+
+type trashEntry struct {
+	Repository string `json:"repository"`
+	// Branch is empty for a snapshot of a detached worktree.
+	Branch    string    `json:"branch,omitempty"`
+	Selector  string    `json:"selector"`
+	Ref       string    `json:"ref"`
+	Commit    string    `json:"commit"`
+	Head      string    `json:"head"`
+	TrashedAt time.Time `json:"trashed_at"`
+}
+
+// trashItem is one snapshot together with the repository that holds it.
+type trashItem struct {
+	repository *catalog.Repository
+	snapshot   gitx.TrashEntry
+}
+
+// selector names the snapshot the way rm named the worktree it came from.
+func (t trashItem) selector() string {
+	if t.snapshot.Branch == "" {
+		return t.repository.Name + ":@" + shortSHA(t.snapshot.Head)
+	}
+	return t.repository.Name + ":" + t.snapshot.Branch
+}
+
+func (t trashItem) entry() trashEntry {
+	return trashEntry{
+		Repository: t.repository.Name,
+		Branch:     t.snapshot.Branch,
+		Selector:   t.selector(),
+		Ref:        t.snapshot.Ref,
+		Commit:     t.snapshot.Commit,
+		Head:       t.snapshot.Head,
+		TrashedAt:  t.snapshot.At,
+	}
+}
+
+func (a *application) restoreCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore [selector]",
+		Short: "Recreate a worktree removed with --discard from its trash snapshot",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runRestore(cmd, args)
+		},
+	}
+}
+
+func (a *application) trashCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "trash",
+		Short: "List and purge snapshots of discarded worktrees",
+		Args:  cobra.NoArgs,
+	}
+	command.AddCommand(a.trashListCommand(), a.trashPurgeCommand())
+	return command
+}
+
+func (a *application) trashListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List trash snapshots, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return a.runTrashList(cmd)
+		},
+	}
+}
+
+func (a *application) trashPurgeCommand() *cobra.Command {
+	var olderThanValue string
+	var dryRun bool
+	command := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete trash snapshots older than a duration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			olderThan, err := parseOlderThan(olderThanValue)
+			if err != nil {
+				return err
+			}
+			if olderThan.duration == 0 {
+				return fmt.Errorf("--older-than is required")
+			}
+			return a.runTrashPurge(cmd, olderThan, dryRun)
+		},
+	}
+	command.Flags().StringVar(&olderThanValue, "older-than", "", "Delete snapshots taken longer ago than a duration such as 30d or 8w")
+	command.Flags().BoolVar(&dryRun, "dry-run", false, "Preview purging without deleting anything")
+	return command
+}
+
+func (a *application) runRestore(cmd *cobra.Command, args []string) error {
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	items := scanTrash(cmd, context.catalog.Repositories)
+	var item trashItem
+	if len(args) != 0 {
+		item, err = findTrash(context.catalog, items, args[0])
+	} else {
+		item, err = a.pickTrash(items)
+	}
+	if err != nil {
+		return err
+	}
+	path, branch, err := item.repository.Git.RestoreTrash(item.snapshot)
+	if err != nil {
+		if path != "" {
+			return fmt.Errorf("%s: restoring into %s: %w", item.selector(), path, err)
+		}
+		return fmt.Errorf("%s: %w", item.selector(), err)
+	}
+	if err := item.repository.Git.DeleteTrash(item.snapshot.Ref); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: keeping %s: %v\n", item.snapshot.Ref, err)
+	}
+	// A restored worktree holds the only copy of its snapshot, so setup
+	// failures only warn whatever the setup_failure policy says.
+	target := setupTarget{repository: item.repository, profile: item.repository.DefaultProfile(), branch: branch, path: path}
+	if _, err := runSetup(cmd, target); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+	}
+	runPostCreateHook(cmd, target)
+	if a.jsonOutput {
+		return writeJSON(cmd, restoreOutput{Version: 1, Repository: item.repository.Name, Branch: branch, Path: path, Trash: item.snapshot.Ref})
+	}
+	return a.writePath(cmd, path)
+}
+
+// findTrash resolves a restore selector to the newest matching snapshot. A
+// selector is a branch, repo:branch, repo:@<head> for a detached worktree, or
+// a full refs/grove/trash ref.
+func findTrash(cat *catalog.Catalog, items []trashItem, selector string) (trashItem, error) {
+	if strings.HasPrefix(selector, gitx.TrashRefPrefix) {
+		for _, item := range items {
+			if item.snapshot.Ref == selector {
+				return item, nil
+			}
+		}
+		return trashItem{}, fmt.Errorf("trash snapshot %s not found", selector)
+	}
+	repository := cat.Current
+	name := selector
+	if strings.Contains(selector, ":") {
+		parts := strings.SplitN(selector, ":", 2)
+		var err error
+		if repository, _, err = cat.FindRepository(parts[0]); err != nil {
+			return trashItem{}, err
+		}
+		name = parts[1]
+	} else if repository == nil {
+		return trashItem{}, fmt.Errorf("not inside a Git repository; use repo:branch")
+	}
+	if name == "" || name == "@" {
+		return trashItem{}, fmt.Errorf("selector %q names no branch", selector)
+	}
+	for _, item := range items {
+		if item.repository != repository {
+			continue
+		}
+		if head, detached := strings.CutPrefix(name, "@"); detached {
+			if item.snapshot.Branch == "" && strings.HasPrefix(item.snapshot.Head, head) {
+				return item, nil
+			}
+		} else if item.snapshot.Branch == name {
+			return item, nil
+		}
+	}
+	return trashItem{}, fmt.Errorf("no trash snapshot for %s", selector)
+}
+
+func (a *application) pickTrash(items []trashItem) (trashItem, error) {
+	if a.noInput || !a.dependencies.interactive() {
+		return trashItem{}, fmt.Errorf("selector is required in non-interactive mode")
+	}
+	if len(items) == 0 {
+		return trashItem{}, fmt.Errorf("trash is empty")
+	}
+	width := 0
+	for _, item := range items {
+		width = max(width, len(item.selector()))
+	}
+	now := time.Now()
+	choices := make([]picker.Item, 0, len(items))
+	for index, item := range items {
+		choices = append(choices, picker.Item{
+			Key:   strconv.Itoa(index),
+			Label: fmt.Sprintf("%-*s  trashed %s ago", width, item.selector(), relativeAge(now.Sub(item.snapshot.At))),
+		})
+	}
+	selection, err := a.dependencies.pick("restore > ", choices, false)
+	if err != nil {
+		return trashItem{}, err
+	}
+	index, err := strconv.Atoi(selection.Key)
+	if err != nil || index < 0 || index >= len(items) {
+		return trashItem{}, fmt.Errorf("unknown trash selection %q", selection.Key)
+	}
+	return items[index], nil
+}
+
+func (a *application) runTrashList(cmd *cobra.Command) error {
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	items := scanTrash(cmd, context.catalog.Repositories)
+	entries := make([]trashEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, item.entry())
+	}
+	if a.jsonOutput {
+		return writeJSON(cmd, trashListOutput{Version: 1, Entries: entries})
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "Trash is empty.")
+		return nil
+	}
+	style := a.style(cmd.OutOrStdout())
+	now := time.Now()
+	for _, entry := range entries {
+		detail := "trashed " + relativeAge(now.Sub(entry.TrashedAt)) + " ago  " + shortSHA(entry.Commit)
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s\n", style.branch(entry.Selector), style.muted(detail))
+	}
+	return nil
+}
+
+func (a *application) runTrashPurge(cmd *cobra.Command, olderThan cleanupAge, dryRun bool) error {
+	context, err := a.loadContext(cmd)
+	if err != nil {
+		return err
+	}
+	repositories := make([]*catalog.Repository, 0, len(context.catalog.Repositories))
+	for _, repository := range context.catalog.Repositories {
+		if context.catalog.Current == repository && !context.catalog.CurrentRegistered {
+			continue
+		}
+		repositories = append(repositories, repository)
+	}
+	now := time.Now()
+	results := make([]trashEntry, 0)
+	var failures []error
+	for _, item := range scanTrash(cmd, repositories) {
+		if now.Sub(item.snapshot.At) < olderThan.duration {
+			continue
+		}
+		if !dryRun {
+			if err := item.repository.Git.DeleteTrash(item.snapshot.Ref); err != nil {
+				failures = append(failures, fmt.Errorf("%s: %w", item.selector(), err))
+				continue
+			}
+		}
+		results = append(results, item.entry())
+	}
+
+	if a.jsonOutput {
+		output := trashPurgeOutput{Version: 1, DryRun: dryRun, Purged: []trashEntry{}, WouldPurge: []trashEntry{}}
+		if dryRun {
+			output.WouldPurge = results
+		} else {
+			output.Purged = results
+		}
+		if err := writeJSON(cmd, output); err != nil {
+			return err
+		}
+	} else if len(results) == 0 {
+		if dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "No snapshots older than %s would be purged.\n", olderThan.label)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "No snapshots older than %s purged.\n", olderThan.label)
+		}
+	} else {
+		action := "Purged"
+		if dryRun {
+			action = "Would purge"
+		}
+		style := a.style(cmd.OutOrStdout())
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s older than %s\n", style.info(action), worktreeCount(len(results), "snapshot", "snapshots"), olderThan.label)
+		for _, result := range results {
+			detail := "trashed " + relativeAge(now.Sub(result.TrashedAt)) + " ago"
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s\n", style.branch(result.Selector), style.muted(detail))
+		}
+	}
+	if len(failures) != 0 {
+		return errors.Join(failures...)
+	}
+	return nil
+}
+
+// scanTrash reads the snapshots of every repository, newest first. A
+// repository that cannot be read is reported as a warning and skipped.
+func scanTrash(cmd *cobra.Command, repositories []*catalog.Repository) []trashItem {
+	var items []trashItem
+	for _, repository := range repositories {
+		snapshots, err := repository.Git.TrashEntries()
+		if err != nil {
+			failure := inventory.Failure{Repository: repository.Name, Err: err}
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", failure.Error())
+			continue
+		}
+		for _, snapshot := range snapshots {
+			items = append(items, trashItem{repository: repository, snapshot: snapshot})
+		}
+	}
+	sort.SliceStable(items, func(left, right int) bool {
+		return items[left].snapshot.At.After(items[right].snapshot.At)
+	})
+	return items
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoveDiscardSnapshotsForRestore(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	for _, branch := range []string{"lost", "old"} {
+		root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+		path, _, err := executeV2(root, "new", branch)
+		if err != nil {
+			t.Fatalf("new %s error = %v", branch, err)
+		}
+		if err := os.WriteFile(filepath.Join(strings.TrimSpace(path), "draft.txt"), []byte(branch), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	_, stderr, err := executeV2(root, "rm", "--discard", "--delete-branch", "feat/lost")
+	if err != nil || !strings.Contains(stderr, "grove restore app:feat/lost") {
+		t.Fatalf("rm --discard = %v, stderr %q", err, stderr)
+	}
+	if branches := v2GitOutput(t, repoPath, "branch", "--list", "feat/lost"); branches != "" {
+		t.Fatalf("branch survived rm --delete-branch: %q", branches)
+	}
+	t.Setenv("GIT_COMMITTER_DATE", "2020-01-01T00:00:00Z")
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "--discard", "feat/old"); err != nil {
+		t.Fatalf("rm --discard old error = %v", err)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "trash", "list")
+	if err != nil {
+		t.Fatalf("trash list error = %v", err)
+	}
+	var list trashListOutput
+	if err := json.Unmarshal([]byte(stdout), &list); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(list.Entries) != 2 || list.Entries[0].Selector != "app:feat/lost" || !strings.HasPrefix(list.Entries[0].Ref, "refs/grove/trash/feat/lost/") {
+		t.Fatalf("trash entries = %#v", list.Entries)
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "restore", "feat/lost")
+	if err != nil {
+		t.Fatalf("restore error = %v", err)
+	}
+	restored := strings.TrimSpace(stdout)
+	if data, err := os.ReadFile(filepath.Join(restored, "draft.txt")); err != nil || string(data) != "lost" {
+		t.Fatalf("restored draft = %q, %v", data, err)
+	}
+	if branch := v2GitOutput(t, restored, "branch", "--show-current"); branch != "feat/lost" {
+		t.Fatalf("restored branch = %q", branch)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "restore", "feat/lost"); err == nil {
+		t.Fatal("restore succeeded twice for one snapshot")
+	}
+
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err = executeV2(root, "trash", "purge", "--older-than", "30d")
+	if err != nil || !strings.Contains(stdout, "Purged 1 snapshot older than 30d") || !strings.Contains(stdout, "app:feat/old") {
+		t.Fatalf("trash purge = %v, stdout %q", err, stdout)
+	}
+	if refs := v2GitOutput(t, repoPath, "for-each-ref", "refs/grove/trash"); refs != "" {
+		t.Fatalf("trash refs after purge = %q", refs)
+	}
+}
+
+func TestRemoveNoTrashSkipsSnapshot(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "new", "gone"); err != nil {
+		t.Fatalf("new error = %v", err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "--no-trash", "feat/gone"); err == nil {
+		t.Fatal("rm --no-trash without --discard succeeded")
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "rm", "--discard", "--no-trash", "feat/gone"); err != nil {
+		t.Fatalf("rm --discard --no-trash error = %v", err)
+	}
+	if refs := v2GitOutput(t, repoPath, "for-each-ref", "refs/grove/trash"); refs != "" {
+		t.Fatalf("trash refs after --no-trash = %q", refs)
+	}
+}
+
+func TestRemoveDiscardSkipsSnapshotOfCleanWorktree(t *testing.T) {
+	repoPath := initV2Repo(t)
+	writeV2Config(t, repoPath, "")
+	root := newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	if _, _, err := executeV2(root, "new", "clean"); err != nil {
+		t.Fatalf("new error = %v", err)
+	}
+	root = newRootCommand(commandDependencies{getwd: func() (string, error) { return repoPath, nil }})
+	stdout, _, err := executeV2(root, "--json", "rm", "--discard", "feat/clean")
+	if err != nil {
+		t.Fatalf("rm --discard error = %v", err)
+	}
+	var output removeOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(output.Removed) != 1 || output.Removed[0].Trash != "" {
+		t.Fatalf("removed = %#v", output.Removed)
+	}
+	if refs := v2GitOutput(t, repoPath, "for-each-ref", "refs/grove/trash"); refs != "" {
+		t.Fatalf("trash refs after removing a clean worktree = %q", refs)
+	}
+}
@@ -12,6 +12,22 @@ function __gv_flag_enabled
     test "$enabled" = true
 end
 
+# An option that takes a value, such as --merged=squash, is enabled by any
+# value but the false spellings.
+function __gv_option_enabled
+    set -l name $argv[1]
+    set -l enabled false
+    for argument in $argv[2..]
+        if test "$argument" = "$name"; or string match -q -- "$name=*" "$argument"
+            set enabled true
+            if contains -- (string replace -- "$name=" "" "$argument") 0 f F false False FALSE
+                set enabled false
+            end
+        end
+    end
+    test "$enabled" = true
+end
+
 function __gv_option_present
     set -l name $argv[1]
     for argument in $argv[2..]
@@ -56,8 +72,26 @@ function gv
             or return $grove_status
             test -n "$path"
             and builtin cd -- $path
+        case mv
+            set -l path (grove --null mv $rest | string split0)
+            set -l grove_status $pipestatus[1]
+            test $grove_status -eq 0
+            or return $grove_status
+            test -n "$path"
+            and builtin cd -- $path
+        case adopt
+            if __gv_flag_enabled --dry-run $rest
+                grove adopt $rest
+                return $status
+            end
+            set -l path (grove --null adopt $rest | string split0)
+            set -l grove_status $pipestatus[1]
+            test $grove_status -eq 0
+            or return $grove_status
+            test -n "$path"
+            and builtin cd -- $path
         case rm
-            if __gv_flag_enabled --merged $rest; or __gv_flag_enabled --missing $rest; or __gv_option_present --older-than $rest; or __gv_flag_enabled --dry-run $rest
+            if __gv_option_enabled --merged $rest; or __gv_flag_enabled --missing $rest; or __gv_option_present --older-than $rest; or __gv_flag_enabled --dry-run $rest
                 grove rm $rest
                 return $status
             end
@@ -21,11 +21,15 @@ func TestFishWrapperRoutesFlags(t *testing.T) {
 		{name: "json true", args: "new --json=true", direct: true},
 		{name: "json false", args: "new --json=false", direct: false},
 		{name: "merged true", args: "rm --merged=true --dry-run=true", direct: true},
+		{name: "merged squash", args: "rm --merged=squash", direct: true},
+		{name: "merged bare", args: "rm --merged", direct: true},
 		{name: "merged false", args: "rm --merged=false .", direct: false},
 		{name: "older than", args: "rm --older-than 14d", direct: true},
 		{name: "older than equals", args: "rm --older-than=14d", direct: true},
 		{name: "missing true", args: "rm --missing=true", direct: true},
 		{name: "missing false", args: "rm --missing=false .", direct: false},
+		{name: "adopt", args: "adopt .", direct: false},
+		{name: "adopt dry run", args: "adopt --dry-run .", direct: true},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			target := filepath.Join(t.TempDir(), "line\nbreak")
@@ -29,13 +29,23 @@ type Profile struct {
 	Path          string
 	Workdir       string
 	DefaultBranch string
-	Setup         []string
+	Setup         []config.SetupStep
+	SetupFailure  string
+	Hooks         config.Hooks
+	PullRef       string
+	Remotes       []string
+	Copy          []string
+	Link          []string
+	CloneDirs     []string
 }
 
 type Repository struct {
 	Name           string
 	Git            *gitx.Repository
 	DefaultBranch  string
+	PullRef        string
+	Fetch          bool
+	Remotes        []string
 	Profiles       []*Profile
 	defaultProfile *Profile
 	defaultScore   int
@@ -102,6 +112,15 @@ func Build(cfg *config.Config, currentDir string) (*Catalog, []Warning) {
 				warnings = append(warnings, Warning{Name: name, Path: row.Path, Message: "repository path must be absolute or start with ~/"})
 				continue
 			}
+			remotes := row.RemoteNames()
+			if err := validateRemotes(remotes); err != nil {
+				warnings = append(warnings, Warning{Name: name, Path: row.Path, Message: err.Error()})
+				continue
+			}
+			if err := validateSetup(row); err != nil {
+				warnings = append(warnings, Warning{Name: name, Path: row.Path, Message: err.Error()})
+				continue
+			}
 			repo, err := gitx.OpenRepository(row.Path)
 			if err != nil {
 				warnings = append(warnings, Warning{Name: name, Path: row.Path, Message: err.Error()})
@@ -115,7 +134,14 @@ func Build(cfg *config.Config, currentDir string) (*Catalog, []Warning) {
 				Path:          row.Path,
 				Workdir:       filepath.Clean(row.Workdir),
 				DefaultBranch: row.DefaultBranch,
-				Setup:         append([]string(nil), row.Setup...),
+				Setup:         append([]config.SetupStep(nil), row.Setup...),
+				SetupFailure:  row.SetupFailure,
+				Hooks:         row.Hooks,
+				PullRef:       row.PullRef,
+				Remotes:       remotes,
+				Copy:          append([]string(nil), row.Copy...),
+				Link:          append([]string(nil), row.Link...),
+				CloneDirs:     append([]string(nil), row.CloneDirs...),
 			}
 			if row.Workdir == "" {
 				profile.Workdir = ""
@@ -128,12 +154,16 @@ func Build(cfg *config.Config, currentDir string) (*Catalog, []Warning) {
 				catalog.Repositories = append(catalog.Repositories, entry)
 			}
 			entry.Profiles = append(entry.Profiles, profile)
+			entry.Fetch = entry.Fetch || row.Fetch
 			score := profileScore(repo.MainPath, row.Path, profile.Workdir)
 			if entry.defaultProfile == nil || score > entry.defaultScore {
 				entry.defaultProfile = profile
 				entry.defaultScore = score
 				entry.Name = catalog.uniqueRepositoryName(name, entry)
 				entry.DefaultBranch = row.DefaultBranch
+				entry.PullRef = row.PullRef
+				entry.Remotes = remotes
+				entry.Git.Remotes = remotes
 			}
 			if catalog.bind(name, entry, profile) {
 				warnings = append(warnings, Warning{Name: name, Path: row.Path, Message: "duplicate alias refers to more than one repository"})
@@ -149,7 +179,7 @@ func Build(cfg *config.Config, currentDir string) (*Catalog, []Warning) {
 				catalog.CurrentRegistered = true
 			} else {
 				name := catalog.UniqueName(filepath.Base(current.MainPath))
-				profile := &Profile{Name: name, Path: current.MainPath, DefaultBranch: gitx.DefaultBranch(current.MainPath)}
+				profile := &Profile{Name: name, Path: current.MainPath, DefaultBranch: gitx.DefaultBranch(current.MainPath, gitx.DefaultRemote)}
 				entry := &Repository{
 					Name:           name,
 					Git:            current,
@@ -228,6 +258,27 @@ func (c *Catalog) FindRepository(name string) (*Repository, *Profile, error) {
 	return first.repository, first.profile, nil
 }
 
+// Lookup returns the repository that owns the Git common directory of repo.
+func (c *Catalog) Lookup(repo *gitx.Repository) *Repository {
+	if repo == nil {
+		return nil
+	}
+	return c.byCommon[repo.CommonDir]
+}
+
+// NameConflicts reports whether binding name to repository would make the name
+// ambiguous, because it already selects a different repository or is reserved
+// by a configured row that did not resolve.
+func (c *Catalog) NameConflicts(name string, repository *Repository) bool {
+	bindings := c.bindings[name]
+	for _, existing := range bindings {
+		if repository == nil || existing.repository != repository {
+			return true
+		}
+	}
+	return len(bindings) == 0 && c.reservedNames[name]
+}
+
 func (c *Catalog) UniqueName(base string) string {
 	base = strings.TrimSpace(base)
 	if base == "" {
@@ -294,3 +345,42 @@ func profileScore(mainPath, configuredPath, workdir string) int {
 	}
 	return 20
 }
+
+func validateRemotes(remotes []string) error {
+	for _, remote := range remotes {
+		if err := config.ValidateRemoteName(remote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSetup(row config.RepoConfig) error {
+	if err := config.ValidateSetupFailure(row.SetupFailure); err != nil {
+		return err
+	}
+	steps := append([]config.SetupStep(nil), row.Setup...)
+	for _, hook := range [][]config.SetupStep{row.Hooks.PostCreate, row.Hooks.PreRemove, row.Hooks.PostRemove, row.Hooks.PostVisit} {
+		steps = append(steps, hook...)
+	}
+	for _, step := range steps {
+		if strings.TrimSpace(step.Run) == "" {
+			return fmt.Errorf("setup and hook steps need a run command")
+		}
+		if _, err := step.TimeoutDuration(); err != nil {
+			return err
+		}
+	}
+	lists := []struct {
+		key      string
+		patterns []string
+	}{{"copy", row.Copy}, {"link", row.Link}, {"clone_dirs", row.CloneDirs}}
+	for _, list := range lists {
+		for _, pattern := range list.patterns {
+			if err := config.ValidateFilePattern(list.key, pattern); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -18,9 +18,9 @@ func TestBuildDeduplicatesRepositoryAndPreservesProfiles(t *testing.T) {
 		t.Fatal(err)
 	}
 	cfg := &config.Config{Repos: []config.RepoConfig{
-		{Path: repoPath, Name: "agent", Workdir: "packages/agent", DefaultBranch: "main", Setup: []string{"agent-setup"}},
-		{Path: repoPath + string(os.PathSeparator), Name: "main", DefaultBranch: "main", Setup: []string{"root-setup"}},
-		{Path: nested, Name: "patches", Workdir: "packages/agent", Setup: []string{"patch-setup"}},
+		{Path: repoPath, Name: "agent", Workdir: "packages/agent", DefaultBranch: "main", Setup: config.SetupCommands([]string{"agent-setup"})},
+		{Path: repoPath + string(os.PathSeparator), Name: "main", DefaultBranch: "main", Setup: config.SetupCommands([]string{"root-setup"})},
+		{Path: nested, Name: "patches", Workdir: "packages/agent", Setup: config.SetupCommands([]string{"patch-setup"})},
 	}}
 
 	got, warnings := Build(cfg, nested)
@@ -82,18 +82,22 @@ func TestBuildWarnsAndSkipsMalformedRows(t *testing.T) {
 		{Path: "relative/repo", Name: "relative"},
 		{Path: repoPath, Name: "unknown", Type: "mystery"},
 		{Path: t.TempDir(), Name: "legacy", Type: "plain"},
-		{Path: repoPath, Name: "valid"},
+		{Path: repoPath, Name: "badremote", Remote: "--upload-pack=x"},
+		{Path: repoPath, Name: "valid", Remote: "upstream", Remotes: []string{"upstream", "origin"}},
 	}}
 
 	got, warnings := Build(cfg, repoPath)
 	if len(got.Repositories) != 1 || got.Repositories[0].Name != "valid" {
 		t.Fatalf("Repositories = %#v, want only valid", got.Repositories)
 	}
-	if len(warnings) != 3 {
-		t.Fatalf("warnings = %#v, want blank, relative, and unknown-type warnings", warnings)
+	if remotes := got.Repositories[0].Git.Remotes; len(remotes) != 2 || remotes[0] != "upstream" || remotes[1] != "origin" {
+		t.Fatalf("Remotes = %q, want upstream then origin", remotes)
+	}
+	if len(warnings) != 4 {
+		t.Fatalf("warnings = %#v, want blank, relative, unknown-type, and remote warnings", warnings)
 	}
 	text := fmt.Sprint(warnings)
-	for _, want := range []string{"blank", "relative", "mystery"} {
+	for _, want := range []string{"blank", "relative", "mystery", "invalid remote"} {
 		if !strings.Contains(text, want) {
 			t.Fatalf("warnings = %q, missing %q", text, want)
 		}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/gofrs/flock"
@@ -16,12 +17,46 @@ type Config struct {
 }
 
 type RepoConfig struct {
-	Path          string   `yaml:"path"`
-	Name          string   `yaml:"name"`
-	Type          string   `yaml:"type"`
-	DefaultBranch string   `yaml:"default_branch"`
-	Workdir       string   `yaml:"workdir"`
-	Setup         []string `yaml:"setup"`
+	Path          string      `yaml:"path"`
+	Name          string      `yaml:"name"`
+	Type          string      `yaml:"type"`
+	DefaultBranch string      `yaml:"default_branch"`
+	Workdir       string      `yaml:"workdir"`
+	Setup         []SetupStep `yaml:"setup"`
+	// SetupFailure is warn, fail, or remove; see the SetupFailure constants.
+	SetupFailure string `yaml:"setup_failure"`
+	Hooks        Hooks  `yaml:"hooks"`
+	// PullRef is the remote ref pattern for `grove new --pr`; {number} is
+	// replaced with the pull request number.
+	PullRef string `yaml:"pull_ref"`
+	// Fetch makes `grove new` and `grove rm --merged` fetch the repository
+	// first, as if --fetch were given.
+	Fetch bool `yaml:"fetch"`
+	// Remote is the canonical remote, origin when empty. Remotes lists
+	// further remotes searched, in order, for existing branches.
+	Remote  string   `yaml:"remote"`
+	Remotes []string `yaml:"remotes"`
+	// Copy and Link are glob patterns, relative to the main checkout, of
+	// untracked files that `grove new` copies or symlinks into new worktrees.
+	Copy []string `yaml:"copy"`
+	Link []string `yaml:"link"`
+	// CloneDirs are directories, such as node_modules, that `grove new` seeds
+	// from the main checkout or the most recently visited sibling worktree.
+	CloneDirs []string `yaml:"clone_dirs"`
+}
+
+// RemoteNames returns the remotes to search in order, canonical remote first.
+func (r RepoConfig) RemoteNames() []string {
+	names := make([]string, 0, 1+len(r.Remotes))
+	for _, name := range append([]string{r.Remote}, r.Remotes...) {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, "origin")
+	}
+	return names
 }
 
 func DefaultConfigPath() (string, error) {
@@ -37,26 +72,34 @@ func NewWorktreeRepo(path, name, defaultBranch string) RepoConfig {
 		Path:          path,
 		Name:          name,
 		DefaultBranch: defaultBranch,
-		Setup:         []string{},
+		Setup:         []SetupStep{},
 	}
 }
 
 func AddRepoToFile(path string, repo RepoConfig) error {
-	if strings.TrimSpace(repo.Path) == "" {
-		return fmt.Errorf("repo path is required")
-	}
-	if strings.TrimSpace(repo.Name) == "" {
-		return fmt.Errorf("repo name is required")
+	return AddReposToFile(path, []RepoConfig{repo})
+}
+
+// AddReposToFile appends several rows under one config lock and one write, so
+// either every row is added or none is.
+func AddReposToFile(path string, repos []RepoConfig) error {
+	for _, repo := range repos {
+		if strings.TrimSpace(repo.Path) == "" {
+			return fmt.Errorf("repo path is required")
+		}
+		if strings.TrimSpace(repo.Name) == "" {
+			return fmt.Errorf("repo name is required")
+		}
 	}
 	lock, err := lockConfig(path)
 	if err != nil {
 		return fmt.Errorf("locking config: %w", err)
 	}
 	defer unlockConfig(lock)
-	return addRepoToFileLocked(path, repo)
+	return addReposToFileLocked(path, repos)
 }
 
-func addRepoToFileLocked(path string, repo RepoConfig) error {
+func addReposToFileLocked(path string, repos []RepoConfig) error {
 	if _, err := os.Stat(path); err != nil {
 		if !os.IsNotExist(err) {
 			return fmt.Errorf("stat config: %w", err)
@@ -77,13 +120,158 @@ func addRepoToFileLocked(path string, repo RepoConfig) error {
 	if err := cfg.resolve(); err != nil {
 		return err
 	}
-	if err := rejectDuplicateRepo(&cfg, repo); err != nil {
+	for _, repo := range repos {
+		if err := rejectDuplicateRepo(&cfg, repo); err != nil {
+			return err
+		}
+		data, err = appendRepoEntry(data, repo)
+		if err != nil {
+			return err
+		}
+		cfg.Repos = append(cfg.Repos, repo)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
+
+// RemoveRepoFromFile deletes the single row named name and returns it. Rows
+// are matched by their resolved name, so an entry without an explicit name is
+// addressed by its directory name exactly as the catalog binds it.
+func RemoveRepoFromFile(path, name string) (RepoConfig, error) {
+	var removed RepoConfig
+	err := editRepoEntries(path, func(cfg *Config, reposNode *yaml.Node) error {
+		index, err := findRepoIndex(cfg, name)
+		if err != nil {
+			return err
+		}
+		removed = cfg.Repos[index]
+		reposNode.Content = append(reposNode.Content[:index], reposNode.Content[index+1:]...)
+		return nil
+	})
+	return removed, err
+}
+
+// RenameRepoInFile changes the name of the single row named oldName. The new
+// name must not already belong to another row.
+func RenameRepoInFile(path, oldName, newName string) (RepoConfig, error) {
+	if err := ValidateRepoName(newName); err != nil {
+		return RepoConfig{}, err
+	}
+	var renamed RepoConfig
+	err := editRepoEntries(path, func(cfg *Config, reposNode *yaml.Node) error {
+		index, err := findRepoIndex(cfg, oldName)
+		if err != nil {
+			return err
+		}
+		for other, existing := range cfg.Repos {
+			if other != index && existing.Name == newName {
+				return fmt.Errorf("repo name %s already exists", newName)
+			}
+		}
+		entry := reposNode.Content[index]
+		if entry.Kind != yaml.MappingNode {
+			return fmt.Errorf("config repo %s must be a mapping", oldName)
+		}
+		if _, value := mappingValue(entry, "name"); value != nil {
+			value.Kind, value.Tag, value.Style, value.Value = yaml.ScalarNode, "!!str", 0, newName
+		} else {
+			entry.Content = append(entry.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "name"},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: newName},
+			)
+		}
+		renamed = cfg.Repos[index]
+		renamed.Name = newName
+		return nil
+	})
+	return renamed, err
+}
+
+// ValidateRepoName rejects names that cannot be used as a selector prefix.
+func ValidateRepoName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("repo name is required")
+	}
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("invalid repo name %q: leading or trailing whitespace is not allowed", name)
+	}
+	if strings.ContainsAny(name, ":\r\n") {
+		return fmt.Errorf("invalid repo name %q: ':' and newlines are not allowed", name)
+	}
+	return nil
+}
+
+// ValidateRemoteName rejects remote names that cannot appear in a
+// refs/remotes/<remote>/<branch> path or be passed safely to git fetch.
+func ValidateRemoteName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, "/ \t\r\n:") {
+		return fmt.Errorf("invalid remote name %q", name)
+	}
+	return nil
+}
+
+func findRepoIndex(cfg *Config, name string) (int, error) {
+	found := -1
+	for index, repo := range cfg.Repos {
+		if repo.Name != name {
+			continue
+		}
+		if found >= 0 {
+			return -1, fmt.Errorf("repo name %s matches more than one config entry; edit the config to disambiguate", name)
+		}
+		found = index
+	}
+	if found < 0 {
+		return -1, fmt.Errorf("repo %s not found in config", name)
+	}
+	return found, nil
+}
+
+// editRepoEntries applies edit to the repos sequence under the config lock and
+// writes the re-encoded document atomically. Editing the node tree keeps
+// comments and unrelated keys that a struct round-trip would drop.
+func editRepoEntries(path string, edit func(*Config, *yaml.Node) error) error {
+	lock, err := lockConfig(path)
+	if err != nil {
+		return fmt.Errorf("locking config: %w", err)
+	}
+	defer unlockConfig(lock)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("parsing config: %w", err)
+	}
+	if err := cfg.resolve(); err != nil {
 		return err
 	}
-	updated, err := appendRepoEntry(data, repo)
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("parsing config: %w", err)
+	}
+	mapping, err := rootMappingNode(&root)
+	if err != nil {
+		return err
+	}
+	_, reposNode := mappingValue(mapping, "repos")
+	if reposNode == nil || reposNode.Kind != yaml.SequenceNode || len(reposNode.Content) != len(cfg.Repos) {
+		return fmt.Errorf("config repos must be a list")
+	}
+	if err := edit(&cfg, reposNode); err != nil {
+		return err
+	}
+	updated, err := encodeConfigNode(&root)
 	if err != nil {
 		return err
 	}
+	if err := yaml.Unmarshal(updated, &Config{}); err != nil {
+		return fmt.Errorf("generated invalid config: %w", err)
+	}
 	if err := writeFileAtomic(path, updated); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
@@ -181,7 +369,9 @@ func rejectDuplicateRepo(cfg *Config, repo RepoConfig) error {
 		if existing.Name == repo.Name {
 			return fmt.Errorf("repo name %s already exists", repo.Name)
 		}
-		if normalizeRepoPath(existing.Path, home) == repoPath {
+		// Rows sharing a checkout are setup profiles; only an identical workdir
+		// would make the new row indistinguishable from the existing one.
+		if normalizeRepoPath(existing.Path, home) == repoPath && filepath.Clean("./"+existing.Workdir) == filepath.Clean("./"+repo.Workdir) {
 			return fmt.Errorf("repo path %s already exists as %s", repoPath, existing.Name)
 		}
 	}
@@ -269,6 +459,17 @@ func appendRepoNode(root, mapping, reposNode *yaml.Node, repo RepoConfig) ([]byt
 		*reposNode = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", HeadComment: reposNode.HeadComment, LineComment: reposNode.LineComment, FootComment: reposNode.FootComment}
 	}
 	reposNode.Content = append(reposNode.Content, repositoryMappingNode(repo))
+	updated, err := encodeConfigNode(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAppendedRepo(updated, repo); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func encodeConfigNode(root *yaml.Node) ([]byte, error) {
 	var output bytes.Buffer
 	encoder := yaml.NewEncoder(&output)
 	encoder.SetIndent(2)
@@ -278,11 +479,7 @@ func appendRepoNode(root, mapping, reposNode *yaml.Node, repo RepoConfig) ([]byt
 	if err := encoder.Close(); err != nil {
 		return nil, fmt.Errorf("encoding config: %w", err)
 	}
-	updated := output.Bytes()
-	if err := validateAppendedRepo(updated, repo); err != nil {
-		return nil, err
-	}
-	return updated, nil
+	return output.Bytes(), nil
 }
 
 func repositoryMappingNode(repo RepoConfig) *yaml.Node {
@@ -301,10 +498,20 @@ func repositoryMappingNode(repo RepoConfig) *yaml.Node {
 	if repo.Workdir != "" {
 		appendScalar("workdir", repo.Workdir)
 	}
+	if repo.Remote != "" {
+		appendScalar("remote", repo.Remote)
+	}
 	if repo.Setup != nil {
 		sequence := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
-		for _, instruction := range repo.Setup {
-			sequence.Content = append(sequence.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: instruction})
+		for _, step := range repo.Setup {
+			if step.Plain() {
+				sequence.Content = append(sequence.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: step.Run})
+				continue
+			}
+			node := &yaml.Node{}
+			// Encoding a struct of strings and a bool cannot fail.
+			_ = node.Encode(step)
+			sequence.Content = append(sequence.Content, node)
 		}
 		mapping.Content = append(mapping.Content,
 			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "setup"},
@@ -315,13 +522,13 @@ func repositoryMappingNode(repo RepoConfig) *yaml.Node {
 }
 
 func repoNeedsNodeEncoding(repo RepoConfig) bool {
-	for _, value := range []string{repo.Path, repo.Name, repo.DefaultBranch, repo.Workdir} {
+	for _, value := range []string{repo.Path, repo.Name, repo.DefaultBranch, repo.Workdir, repo.Remote} {
 		if strings.ContainsAny(value, "\r\n") {
 			return true
 		}
 	}
-	for _, instruction := range repo.Setup {
-		if strings.ContainsAny(instruction, "\r\n") {
+	for _, step := range repo.Setup {
+		if !step.Plain() || strings.ContainsAny(step.Run, "\r\n") {
 			return true
 		}
 	}
@@ -337,24 +544,12 @@ func validateAppendedRepo(data []byte, want RepoConfig) error {
 		return fmt.Errorf("generated config is missing the repository entry")
 	}
 	got := cfg.Repos[len(cfg.Repos)-1]
-	if got.Path != want.Path || got.Name != want.Name || got.DefaultBranch != want.DefaultBranch || got.Workdir != want.Workdir || !sameStrings(got.Setup, want.Setup) {
+	if got.Path != want.Path || got.Name != want.Name || got.DefaultBranch != want.DefaultBranch || got.Workdir != want.Workdir || got.Remote != want.Remote || !slices.Equal(got.Setup, want.Setup) {
 		return fmt.Errorf("generated config changed repository values")
 	}
 	return nil
 }
 
-func sameStrings(left, right []string) bool {
-	if len(left) != len(right) {
-		return false
-	}
-	for index := range left {
-		if left[index] != right[index] {
-			return false
-		}
-	}
-	return true
-}
-
 func rootMappingNode(root *yaml.Node) (*yaml.Node, error) {
 	if root.Kind == yaml.DocumentNode && len(root.Content) == 0 {
 		root.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
@@ -398,8 +593,15 @@ func renderRepoEntry(repo RepoConfig) string {
 	if repo.Workdir != "" {
 		out.WriteString("    workdir: " + yamlScalar(repo.Workdir) + "\n")
 	}
+	if repo.Remote != "" {
+		out.WriteString("    remote: " + yamlScalar(repo.Remote) + "\n")
+	}
 	if repo.Setup != nil {
-		writeStringList(&out, "setup", repo.Setup)
+		commands := make([]string, 0, len(repo.Setup))
+		for _, step := range repo.Setup {
+			commands = append(commands, step.Run)
+		}
+		writeStringList(&out, "setup", commands)
 	}
 	return out.String()
 }
@@ -229,6 +229,50 @@ func TestAddRepoPreservesEmbeddedNewlines(t *testing.T) {
 	}
 }
 
+func TestSetupStepsAcceptStringsAndMappings(t *testing.T) {
+	cfg, err := parseConfig([]byte(strings.Join([]string{
+		"repos:",
+		"  - path: /code/app",
+		"    setup_failure: remove",
+		"    setup:",
+		"      - bun install",
+		"      - run: make schema",
+		"        timeout: 2m",
+		"        continue_on_error: true",
+		"        when: {exists: schema.sql}",
+		"",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SetupStep{
+		{Run: "bun install"},
+		{Run: "make schema", Timeout: "2m", ContinueOnError: true, When: SetupCondition{Exists: "schema.sql"}},
+	}
+	repo := cfg.Repos[0]
+	if repo.SetupFailure != SetupFailureRemove || len(repo.Setup) != 2 || repo.Setup[0] != want[0] || repo.Setup[1] != want[1] {
+		t.Fatalf("repo = %#v", repo)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "repos: []\n")
+	added := NewWorktreeRepo("/code/other", "other", "main")
+	added.Setup = want
+	if err := AddRepoToFile(path, added); err != nil {
+		t.Fatalf("AddRepoToFile() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = parseConfig(data); err != nil || len(cfg.Repos[0].Setup) != 2 || cfg.Repos[0].Setup[1] != want[1] {
+		t.Fatalf("round trip = %#v, %v\n%s", cfg, err, data)
+	}
+	if strings.Contains(string(data), "run: bun install") {
+		t.Fatalf("plain step not written as a string:\n%s", data)
+	}
+}
+
 func TestAddRepoRejectsDuplicateNameAndPath(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "config.yaml")
 	existingPath := t.TempDir()
@@ -241,6 +285,108 @@ func TestAddRepoRejectsDuplicateNameAndPath(t *testing.T) {
 	}
 }
 
+func TestAddReposWritesAllRowsOrNone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	existingPath := t.TempDir()
+	writeConfigFile(t, path, "repos:\n  - path: "+existingPath+"\n    name: existing\n")
+	firstPath, secondPath := t.TempDir(), t.TempDir()
+	if err := AddReposToFile(path, []RepoConfig{NewWorktreeRepo(firstPath, "first", "main"), NewWorktreeRepo(firstPath, "again", "main")}); err == nil {
+		t.Fatal("duplicate path within one batch accepted")
+	}
+	if data, err := os.ReadFile(path); err != nil || strings.Contains(string(data), "first") {
+		t.Fatalf("rejected batch was partly written: %q, %v", data, err)
+	}
+	if err := AddReposToFile(path, []RepoConfig{NewWorktreeRepo(firstPath, "first", "main"), NewWorktreeRepo(secondPath, "second", "main")}); err != nil {
+		t.Fatalf("AddReposToFile() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil || len(cfg.Repos) != 3 {
+		t.Fatalf("config after batch = %d repos, %v\n%s", len(cfg.Repos), err, data)
+	}
+}
+
+func TestAddRepoAcceptsAnotherProfileForSamePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	existingPath := t.TempDir()
+	writeConfigFile(t, path, "repos:\n  - path: "+existingPath+"\n    name: existing\n")
+	profile := NewWorktreeRepo(existingPath, "agent", "main")
+	profile.Workdir = "packages/agent"
+	if err := AddRepoToFile(path, profile); err != nil {
+		t.Fatalf("AddRepoToFile() error = %v", err)
+	}
+	duplicate := NewWorktreeRepo(existingPath, "agent-2", "main")
+	duplicate.Workdir = "packages/agent/"
+	if err := AddRepoToFile(path, duplicate); err == nil {
+		t.Fatal("duplicate path and workdir accepted")
+	}
+}
+
+func TestRemoveRepoPreservesCommentsAndOtherRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "# custom\nworktree_root: ~/legacy\nrepos:\n  - path: /code/keep # pinned\n    name: keep\n  - path: /code/drop\n    name: drop\n    setup:\n      - make\n")
+	removed, err := RemoveRepoFromFile(path, "drop")
+	if err != nil {
+		t.Fatalf("RemoveRepoFromFile() error = %v", err)
+	}
+	if removed.Path != "/code/drop" || len(removed.Setup) != 1 {
+		t.Fatalf("removed = %#v", removed)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	for _, want := range []string{"# custom", "worktree_root: ~/legacy", "# pinned", "name: keep"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("config missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "drop") {
+		t.Fatalf("removed row remains:\n%s", text)
+	}
+	if _, err := RemoveRepoFromFile(path, "drop"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("second removal error = %v, want not found", err)
+	}
+}
+
+func TestRenameRepoUpdatesNameAndRejectsCollisions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "repos:\n  - path: ~/code/app\n    name: app\n  - path: ~/code/tools\n")
+	renamed, err := RenameRepoInFile(path, "app", "web")
+	if err != nil {
+		t.Fatalf("RenameRepoInFile() error = %v", err)
+	}
+	if renamed.Name != "web" || renamed.Path != filepath.Join(home, "code", "app") {
+		t.Fatalf("renamed = %#v", renamed)
+	}
+	if _, err := RenameRepoInFile(path, "web", "tools"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("colliding rename error = %v", err)
+	}
+	if _, err := RenameRepoInFile(path, "tools", "bad:name"); err == nil {
+		t.Fatal("selector separator accepted in repo name")
+	}
+	if _, err := RenameRepoInFile(path, "tools", "cli"); err != nil {
+		t.Fatalf("renaming implicit name error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("updated config is invalid YAML: %v\n%s", err, data)
+	}
+	if len(cfg.Repos) != 2 || cfg.Repos[0].Name != "web" || cfg.Repos[1].Name != "cli" || cfg.Repos[0].Path != "~/code/app" {
+		t.Fatalf("repos = %#v\n%s", cfg.Repos, data)
+	}
+}
+
 func TestYAMLScalarQuotesTrailingColon(t *testing.T) {
 	if isPlainYAMLScalar("value:") {
 		t.Fatal("trailing colon was treated as a plain YAML scalar")
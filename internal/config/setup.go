@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetupFailure policies decide what `grove new` does when a setup step fails.
+const (
+	SetupFailureWarn   = "warn"
+	SetupFailureFail   = "fail"
+	SetupFailureRemove = "remove"
+)
+
+// SetupStep is one setup command. A plain YAML string is a step with only
+// Run set, so existing `setup: [cmd]` lists keep working.
+type SetupStep struct {
+	Run string `yaml:"run"`
+	// Timeout is a Go duration such as 30s or 5m; empty means no limit.
+	Timeout string `yaml:"timeout,omitempty"`
+	// ContinueOnError records a failure without stopping later steps or
+	// applying the repository's setup_failure policy.
+	ContinueOnError bool           `yaml:"continue_on_error,omitempty"`
+	When            SetupCondition `yaml:"when,omitempty"`
+}
+
+// Hooks are steps run at points in a worktree's life besides setup. They
+// take the same forms and options as setup steps.
+type Hooks struct {
+	PostCreate []SetupStep `yaml:"post_create"`
+	// PreRemove steps veto a removal by failing.
+	PreRemove  []SetupStep `yaml:"pre_remove"`
+	PostRemove []SetupStep `yaml:"post_remove"`
+	PostVisit  []SetupStep `yaml:"post_visit"`
+}
+
+// SetupCondition limits a step to worktrees where Exists names an existing
+// path and Missing names an absent one, both relative to the setup directory.
+type SetupCondition struct {
+	Exists  string `yaml:"exists,omitempty"`
+	Missing string `yaml:"missing,omitempty"`
+}
+
+// SetupCommands returns plain steps for each command.
+func SetupCommands(commands []string) []SetupStep {
+	steps := make([]SetupStep, 0, len(commands))
+	for _, command := range commands {
+		steps = append(steps, SetupStep{Run: command})
+	}
+	return steps
+}
+
+// Plain reports whether the step has no options and encodes as a string.
+func (s SetupStep) Plain() bool {
+	return s == SetupStep{Run: s.Run}
+}
+
+// TimeoutDuration parses Timeout, returning zero when no limit is set.
+func (s SetupStep) TimeoutDuration() (time.Duration, error) {
+	if s.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(s.Timeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("setup step %q: timeout must be a positive duration such as 30s or 5m, got %q", s.Run, s.Timeout)
+	}
+	return timeout, nil
+}
+
+func (s *SetupStep) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = SetupStep{Run: node.Value}
+		return nil
+	}
+	type rawStep SetupStep
+	var raw rawStep
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*s = SetupStep(raw)
+	return nil
+}
+
+func (s SetupStep) MarshalYAML() (any, error) {
+	if s.Plain() {
+		return s.Run, nil
+	}
+	type rawStep SetupStep
+	return rawStep(s), nil
+}
+
+// ValidateSetupFailure accepts the setup_failure policies, empty meaning warn.
+func ValidateSetupFailure(policy string) error {
+	switch policy {
+	case "", SetupFailureWarn, SetupFailureFail, SetupFailureRemove:
+		return nil
+	}
+	return fmt.Errorf("setup_failure must be warn, fail, or remove, got %q", policy)
+}
+
+// ValidateFilePattern accepts a copy, link, or clone_dirs entry: a glob
+// relative to the main checkout that cannot name a path outside it. Key names
+// the list in error messages.
+func ValidateFilePattern(key, pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("%s entries must not be empty", key)
+	}
+	if filepath.IsAbs(pattern) {
+		return fmt.Errorf("%s entries must be relative: %s", key, pattern)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid %s pattern %q: %w", key, pattern, err)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if part == ".." {
+			return fmt.Errorf("%s entries must stay inside the checkout: %s", key, pattern)
+		}
+	}
+	return nil
+}
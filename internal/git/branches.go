@@ -0,0 +1,69 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const createdConfigSuffix = ".grove-created"
+
+// BranchInfo describes one local branch.
+type BranchInfo struct {
+	Name   string
+	Commit string
+	// CommittedAt is the committer date of the branch tip.
+	CommittedAt time.Time
+}
+
+// Branches lists local branches in refname order.
+func (r *Repository) Branches() ([]BranchInfo, error) {
+	out, err := runGitBytes(r.MainPath, "for-each-ref", "--format=%(refname)%00%(objectname)%00%(committerdate:unix)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	return parseBranches(out), nil
+}
+
+func parseBranches(data []byte) []BranchInfo {
+	var branches []BranchInfo
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		fields := strings.Split(string(line), "\x00")
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "refs/heads/") {
+			continue
+		}
+		branch := BranchInfo{Name: strings.TrimPrefix(fields[0], "refs/heads/"), Commit: fields[1]}
+		if seconds, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			branch.CommittedAt = time.Unix(seconds, 0)
+		}
+		branches = append(branches, branch)
+	}
+	return branches
+}
+
+// MarkBranchCreated records that Grove created branch, and when, so branch
+// cleanup can tell it apart from branches made by hand. Like the stack parent,
+// the value disappears with the branch.
+func (r *Repository) MarkBranchCreated(branch string, at time.Time) error {
+	_, err := runGitText(r.MainPath, "config", "branch."+branch+createdConfigSuffix, strconv.FormatInt(at.Unix(), 10))
+	return err
+}
+
+// CreatedBranches maps each branch Grove created to its creation time.
+func (r *Repository) CreatedBranches() (map[string]time.Time, error) {
+	values, err := r.branchConfigValues(createdConfigSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("reading created branches: %w", err)
+	}
+	created := make(map[string]time.Time, len(values))
+	for branch, value := range values {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		created[branch] = time.Unix(seconds, 0)
+	}
+	return created, nil
+}
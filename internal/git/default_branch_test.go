@@ -16,7 +16,7 @@ func TestDefaultBranchPrefersOriginHead(t *testing.T) {
 	runGit(t, repoPath, "remote", "set-head", "origin", "main")
 	runGit(t, repoPath, "checkout", "-b", "feature")
 
-	if got, want := DefaultBranch(repoPath), "main"; got != want {
+	if got, want := DefaultBranch(repoPath, ""), "main"; got != want {
 		t.Fatalf("DefaultBranch() = %q, want %q", got, want)
 	}
 }
@@ -26,7 +26,7 @@ func TestDefaultBranchFallsBackToLocalMain(t *testing.T) {
 	writeCommit(t, repoPath, "base.txt", "base")
 	runGit(t, repoPath, "checkout", "-b", "feature")
 
-	if got, want := DefaultBranch(repoPath), "main"; got != want {
+	if got, want := DefaultBranch(repoPath, ""), "main"; got != want {
 		t.Fatalf("DefaultBranch() = %q, want %q", got, want)
 	}
 }
@@ -36,7 +36,26 @@ func TestDefaultBranchFallsBackToCurrentBranch(t *testing.T) {
 	writeCommit(t, repoPath, "base.txt", "base")
 	runGit(t, repoPath, "branch", "-m", "trunk")
 
-	if got, want := DefaultBranch(repoPath), "trunk"; got != want {
+	if got, want := DefaultBranch(repoPath, ""), "trunk"; got != want {
 		t.Fatalf("DefaultBranch() = %q, want %q", got, want)
 	}
 }
+
+func TestDefaultBranchReadsConfiguredRemoteHead(t *testing.T) {
+	repoPath := initTestRepo(t)
+	writeCommit(t, repoPath, "base.txt", "base")
+	runGit(t, repoPath, "branch", "-m", "trunk")
+
+	upstreamPath := filepath.Join(t.TempDir(), "upstream.git")
+	runGit(t, repoPath, "init", "--bare", upstreamPath)
+	runGit(t, repoPath, "remote", "add", "upstream", upstreamPath)
+	runGit(t, repoPath, "push", "upstream", "trunk:develop")
+	runGit(t, repoPath, "remote", "set-head", "upstream", "develop")
+
+	if got, want := DefaultBranch(repoPath, "upstream"), "develop"; got != want {
+		t.Fatalf("DefaultBranch(upstream) = %q, want %q", got, want)
+	}
+	if got, want := DefaultBranch(repoPath, ""), "trunk"; got != want {
+		t.Fatalf("DefaultBranch(origin) = %q, want %q", got, want)
+	}
+}
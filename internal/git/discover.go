@@ -0,0 +1,51 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DiscoverRepositories finds the main checkouts of Git repositories under
+// root, descending at most maxDepth directories below it. It does not descend
+// into a repository it found, into .wt, or through symlinks, so linked
+// worktrees and nested repositories are never reported. Directories that
+// cannot be read are skipped.
+func DiscoverRepositories(root string, maxDepth int) ([]*Repository, error) {
+	root, err := canonicalPath(root)
+	if err != nil {
+		return nil, err
+	}
+	var repositories []*Repository
+	seen := make(map[string]bool)
+	var walk func(directory string, depth int)
+	walk = func(directory string, depth int) {
+		if _, err := os.Lstat(filepath.Join(directory, ".git")); err == nil {
+			repository, err := OpenRepository(directory)
+			if err == nil && samePath(repository.MainPath, directory) && !seen[repository.CommonDir] {
+				seen[repository.CommonDir] = true
+				repositories = append(repositories, repository)
+			}
+			return
+		}
+		if depth >= maxDepth {
+			return
+		}
+		entries, err := os.ReadDir(directory)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			// DirEntry types come from Lstat, so symlinked directories are skipped.
+			if !entry.IsDir() || entry.Name() == ".wt" || entry.Name() == ".git" {
+				continue
+			}
+			walk(filepath.Join(directory, entry.Name()), depth+1)
+		}
+	}
+	walk(root, 0)
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].MainPath < repositories[j].MainPath
+	})
+	return repositories, nil
+}
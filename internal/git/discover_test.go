@@ -0,0 +1,44 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverRepositoriesRespectsDepthAndSkipsWorktrees(t *testing.T) {
+	root := t.TempDir()
+	shallow := filepath.Join(root, "shallow")
+	deep := filepath.Join(root, "a", "b", "deep")
+	for _, path := range []string{shallow, deep} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, path, "init", "-b", "main")
+		runGit(t, path, "config", "user.name", "Grove Test")
+		runGit(t, path, "config", "user.email", "grove@example.test")
+		writeCommit(t, path, "base.txt", "base")
+	}
+	runGit(t, shallow, "worktree", "add", "-b", "feat/x", filepath.Join(shallow, ".wt", "feat", "x"))
+	runGit(t, shallow, "worktree", "add", "-b", "feat/y", filepath.Join(root, "linked"))
+	nested := filepath.Join(shallow, "vendor", "nested")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, nested, "init", "-b", "main")
+
+	found, err := DiscoverRepositories(root, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].MainPath != canonicalTestPath(t, shallow) {
+		t.Fatalf("DiscoverRepositories(depth 2) = %+v", found)
+	}
+	found, err = DiscoverRepositories(root, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].MainPath != canonicalTestPath(t, deep) {
+		t.Fatalf("DiscoverRepositories(depth 3) = %+v", found)
+	}
+}
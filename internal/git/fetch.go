@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Fetch updates the remote-tracking refs of one remote. Credential prompts are
+// disabled so an unattended fetch fails instead of waiting for input.
+func (r *Repository) Fetch(ctx context.Context, remote string) error {
+	cmd := exec.CommandContext(ctx, "git", "fetch", "--quiet", "--", remote)
+	cmd.Dir = r.MainPath
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.WaitDelay = time.Second
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("fetching %s: timed out", remote)
+	}
+	if err != nil {
+		detail := strings.TrimSpace(string(out))
+		if detail == "" {
+			detail = err.Error()
+		}
+		return fmt.Errorf("fetching %s: %s", remote, detail)
+	}
+	return nil
+}
+
+// FreshBaseRef is BaseRef for callers that just fetched: it prefers the
+// canonical remote's branch unless the local default branch has commits the
+// remote does not.
+func (r *Repository) FreshBaseRef(defaultBranch string) (string, error) {
+	base, err := r.BaseRef(defaultBranch)
+	if err != nil || !strings.HasPrefix(base, "refs/heads/") {
+		return base, err
+	}
+	remote := "refs/remotes/" + r.Remote() + "/" + strings.TrimPrefix(base, "refs/heads/")
+	if !r.RefExists(remote) {
+		return base, nil
+	}
+	behind, err := r.isAncestor(base, remote)
+	if err != nil || !behind {
+		return base, err
+	}
+	return remote, nil
+}
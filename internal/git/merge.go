@@ -0,0 +1,101 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// MergeRule names the evidence that a branch carries no unmerged work.
+type MergeRule string
+
+const (
+	// MergedAncestor means the branch tip is reachable from the target.
+	MergedAncestor MergeRule = "ancestor"
+	// MergedPatchEquivalent means every commit on the branch has a
+	// patch-equivalent commit on the target, as after a rebase merge.
+	MergedPatchEquivalent MergeRule = "patch-equivalent"
+	// MergedSquash means the branch's cumulative diff from its merge-base
+	// appears as a single commit on the target.
+	MergedSquash MergeRule = "squash"
+)
+
+// BranchMergeRule reports how branch is merged into the default branch, or an
+// empty rule when it is not. Ancestry is always checked; squash also accepts
+// rebase and squash merges, which need a patch-id comparison per target.
+// Upstream adds the default branch's remote-tracking ref as a target, as in
+// BranchMerged.
+func (r *Repository) BranchMergeRule(branch, defaultBranch string, squash, upstream bool) (MergeRule, error) {
+	merged, base, err := r.BranchMerged(branch, defaultBranch, upstream)
+	if err != nil {
+		return "", err
+	}
+	if merged {
+		return MergedAncestor, nil
+	}
+	if !squash {
+		return "", nil
+	}
+	targets := []string{base}
+	if remote := "refs/remotes/" + r.Remote() + "/" + strings.TrimPrefix(base, "refs/heads/"); upstream && remote != base && r.RefExists(remote) {
+		targets = append(targets, remote)
+	}
+	for _, target := range targets {
+		rule, err := r.squashMergeRule("refs/heads/"+branch, target)
+		if err != nil || rule != "" {
+			return rule, err
+		}
+	}
+	return "", nil
+}
+
+func (r *Repository) squashMergeRule(ref, target string) (MergeRule, error) {
+	equivalent, err := r.allCommitsUpstream(target, ref)
+	if err != nil {
+		return "", err
+	}
+	if equivalent {
+		return MergedPatchEquivalent, nil
+	}
+	mergeBase, err := runGitText(r.MainPath, "merge-base", target, ref)
+	if err != nil {
+		// Unrelated histories have nothing in common to compare.
+		return "", nil
+	}
+	tree, err := runGitText(r.MainPath, "rev-parse", ref+"^{tree}")
+	if err != nil {
+		return "", err
+	}
+	// A throwaway commit holding the whole branch diff lets git cherry compare
+	// its patch-id with each commit on the target. Git collects it later.
+	cmd := exec.Command("git", "commit-tree", tree, "-p", mergeBase, "-m", "grove squash probe")
+	cmd.Dir = r.MainPath
+	cmd.Env = append(os.Environ(), identityEnv()...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	squashed, err := r.allCommitsUpstream(target, strings.TrimSpace(string(out)))
+	if err != nil || !squashed {
+		return "", err
+	}
+	return MergedSquash, nil
+}
+
+// allCommitsUpstream reports whether every commit in head but not in upstream
+// has a patch-equivalent commit in upstream.
+func (r *Repository) allCommitsUpstream(upstream, head string) (bool, error) {
+	out, err := runGitText(r.MainPath, "cherry", upstream, head)
+	if err != nil {
+		return false, err
+	}
+	if out == "" {
+		return false, nil
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "- ") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
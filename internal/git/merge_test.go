@@ -0,0 +1,38 @@
+package git
+
+import "testing"
+
+func TestBranchMergeRuleDetectsSquashAndRebaseMerges(t *testing.T) {
+	mainPath := initTestRepo(t)
+	writeCommit(t, mainPath, "base.txt", "base")
+	for _, branch := range []string{"feat/squashed", "feat/rebased", "feat/open"} {
+		runGit(t, mainPath, "checkout", "-b", branch, "main")
+		writeCommit(t, mainPath, branch[5:]+"-1.txt", "one")
+		writeCommit(t, mainPath, branch[5:]+"-2.txt", "two")
+	}
+	runGit(t, mainPath, "checkout", "main")
+	writeCommit(t, mainPath, "upstream.txt", "moved on")
+	runGit(t, mainPath, "merge", "--squash", "feat/squashed")
+	runGit(t, mainPath, "commit", "-m", "squash")
+	runGit(t, mainPath, "cherry-pick", "main..feat/rebased")
+	repo, err := OpenRepository(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		branch string
+		squash bool
+		want   MergeRule
+	}{
+		{branch: "feat/squashed", squash: false, want: ""},
+		{branch: "feat/squashed", squash: true, want: MergedSquash},
+		{branch: "feat/rebased", squash: true, want: MergedPatchEquivalent},
+		{branch: "feat/open", squash: true, want: ""},
+	} {
+		got, err := repo.BranchMergeRule(test.branch, "main", test.squash, false)
+		if err != nil || got != test.want {
+			t.Fatalf("BranchMergeRule(%s, squash=%v) = %q, %v; want %q", test.branch, test.squash, got, err, test.want)
+		}
+	}
+}
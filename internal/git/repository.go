@@ -2,7 +2,6 @@ package git
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,9 +10,39 @@ import (
 	"sync"
 )
 
+// DefaultRemote is the remote used when a repository configures none.
+const DefaultRemote = "origin"
+
 type Repository struct {
 	MainPath  string
 	CommonDir string
+	// Remotes are searched in order for existing remote branches. The first
+	// is the canonical remote for the default branch, fetches, and pull
+	// requests; an empty list means DefaultRemote.
+	Remotes []string
+}
+
+// Remote returns the canonical remote.
+func (r *Repository) Remote() string {
+	if len(r.Remotes) == 0 {
+		return DefaultRemote
+	}
+	return r.Remotes[0]
+}
+
+// RemoteBranch returns the remote-tracking ref for branch on the first
+// configured remote that has one.
+func (r *Repository) RemoteBranch(branch string) (string, bool) {
+	remotes := r.Remotes
+	if len(remotes) == 0 {
+		remotes = []string{DefaultRemote}
+	}
+	for _, remote := range remotes {
+		if ref := "refs/remotes/" + remote + "/" + branch; r.RefExists(ref) {
+			return ref, true
+		}
+	}
+	return "", false
 }
 
 func OpenRepository(dir string) (*Repository, error) {
@@ -64,7 +93,7 @@ func (r *Repository) Worktrees() ([]WorktreeInfo, error) {
 }
 
 func (r *Repository) EnsureManagedRoot() (string, error) {
-	tracked, err := runGitBytes(r.MainPath, "ls-files", "-z", "--", ".wt")
+	tracked, err := r.TrackedManagedFiles()
 	if err != nil {
 		return "", err
 	}
@@ -92,6 +121,31 @@ func (r *Repository) EnsureManagedRoot() (string, error) {
 	return root, nil
 }
 
+// TrackedManagedFiles lists index entries under .wt. Any entry makes the
+// managed root unusable because linked worktrees would shadow tracked content.
+func (r *Repository) TrackedManagedFiles() ([]string, error) {
+	out, err := runGitBytes(r.MainPath, "ls-files", "-z", "--", ".wt")
+	if err != nil {
+		return nil, err
+	}
+	var tracked []string
+	for _, path := range bytes.Split(out, []byte{0}) {
+		if len(path) != 0 {
+			tracked = append(tracked, string(path))
+		}
+	}
+	return tracked, nil
+}
+
+// ManagedRootExcluded reports whether the shared exclude file hides .wt.
+func (r *Repository) ManagedRootExcluded() (bool, error) {
+	data, err := os.ReadFile(r.sharedExcludePath())
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("reading shared exclude: %w", err)
+	}
+	return excludesManagedRoot(data), nil
+}
+
 func (r *Repository) ManagedPath(branch string) (string, error) {
 	if err := r.ValidateBranch(branch); err != nil {
 		return "", err
@@ -137,40 +191,28 @@ func (r *Repository) CreateWorktree(branch, startPoint string) (string, bool, er
 	if existing {
 		return destination, false, nil
 	}
-	for _, worktree := range worktrees {
-		if worktree.Main || worktree.Prunable {
-			continue
-		}
-		if pathStrictlyContains(worktree.Path, destination) || pathStrictlyContains(destination, worktree.Path) {
-			return "", false, fmt.Errorf("refusing nested worktree destination %s because it overlaps registered worktree %s", destination, worktree.Path)
-		}
-	}
-	if _, err := os.Lstat(destination); err == nil {
-		return "", false, fmt.Errorf("destination already exists but is not a registered worktree: %s", destination)
-	} else if !os.IsNotExist(err) {
-		return "", false, fmt.Errorf("checking destination: %w", err)
-	}
-	root, err := r.EnsureManagedRoot()
-	if err != nil {
-		return "", false, err
-	}
-	if err := rejectSymlinkComponents(root, destination); err != nil {
+	if err := r.checkWorktreeDestination(destination, worktrees); err != nil {
 		return "", false, err
 	}
 
 	args := []string{"worktree", "add"}
+	remoteBranch, remoteExists := r.RemoteBranch(branch)
 	switch {
 	case r.RefExists("refs/heads/" + branch):
 		if startPoint != "" {
 			return "", false, fmt.Errorf("start point can only be used when creating a new branch; branch %q already exists", branch)
 		}
 		args = append(args, destination, branch)
-	case r.RefExists("refs/remotes/origin/" + branch):
+	case remoteExists:
 		if startPoint != "" {
 			return "", false, fmt.Errorf("start point can only be used when creating a new branch; branch %q already exists", branch)
 		}
-		args = append(args, "--track", "-b", branch, destination, "origin/"+branch)
+		args = append(args, "--track", "-b", branch, destination, strings.TrimPrefix(remoteBranch, "refs/remotes/"))
 	default:
+		if startPoint != "" {
+			// A remote-tracking start point must not become the new branch's upstream.
+			args = append(args, "--no-track")
+		}
 		args = append(args, "-b", branch, destination)
 		if startPoint != "" {
 			args = append(args, startPoint)
@@ -182,6 +224,113 @@ func (r *Repository) CreateWorktree(branch, startPoint string) (string, bool, er
 	return destination, true, nil
 }
 
+// checkWorktreeDestination refuses a managed destination that already exists,
+// overlaps a registered worktree, or passes through a symlink.
+func (r *Repository) checkWorktreeDestination(destination string, worktrees []WorktreeInfo) error {
+	if err := checkDestinationFree(destination, worktrees); err != nil {
+		return err
+	}
+	root, err := r.EnsureManagedRoot()
+	if err != nil {
+		return err
+	}
+	return rejectSymlinkComponents(root, destination)
+}
+
+func checkDestinationFree(destination string, worktrees []WorktreeInfo) error {
+	for _, worktree := range worktrees {
+		if worktree.Main || worktree.Prunable {
+			continue
+		}
+		if pathStrictlyContains(worktree.Path, destination) || pathStrictlyContains(destination, worktree.Path) {
+			return fmt.Errorf("refusing nested worktree destination %s because it overlaps registered worktree %s", destination, worktree.Path)
+		}
+	}
+	if _, err := os.Lstat(destination); err == nil {
+		return fmt.Errorf("destination already exists but is not a registered worktree: %s", destination)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("checking destination: %w", err)
+	}
+	return nil
+}
+
+// PlanWorktreeMove returns where MoveWorktree would put the linked worktree at
+// path for branch, after the checks that need no changes on disk.
+func (r *Repository) PlanWorktreeMove(path, branch string) (string, error) {
+	target, err := canonicalPath(path)
+	if err != nil {
+		return "", err
+	}
+	destination, err := r.ManagedPath(branch)
+	if err != nil {
+		return "", err
+	}
+	worktrees, err := r.Worktrees()
+	if err != nil {
+		return "", err
+	}
+	var found *WorktreeInfo
+	for i := range worktrees {
+		if samePath(worktrees[i].Path, target) {
+			found = &worktrees[i]
+			break
+		}
+	}
+	switch {
+	case found == nil:
+		return "", fmt.Errorf("path is not a registered worktree: %s", target)
+	case found.Main:
+		return "", fmt.Errorf("refusing to move the main worktree: %s", target)
+	case samePath(target, destination):
+		return destination, nil
+	case found.Prunable:
+		return "", fmt.Errorf("worktree directory is missing: %s", target)
+	case found.Locked:
+		if found.LockReason != "" {
+			return "", fmt.Errorf("worktree is locked: %s", found.LockReason)
+		}
+		return "", fmt.Errorf("worktree is locked")
+	}
+	if err := checkDestinationFree(destination, worktrees); err != nil {
+		return "", err
+	}
+	if err := rejectSymlinkComponents(filepath.Join(r.MainPath, ".wt"), destination); err != nil {
+		return "", err
+	}
+	return destination, nil
+}
+
+// MoveWorktree relocates the linked worktree at path to the managed path of
+// branch and returns the new path. A worktree already there is left alone.
+func (r *Repository) MoveWorktree(path, branch string) (string, error) {
+	destination, err := r.PlanWorktreeMove(path, branch)
+	if err != nil {
+		return "", err
+	}
+	target, err := canonicalPath(path)
+	if err != nil {
+		return "", err
+	}
+	if samePath(target, destination) {
+		return destination, nil
+	}
+	root, err := r.EnsureManagedRoot()
+	if err != nil {
+		return "", err
+	}
+	// git worktree move renames into place but does not create parents.
+	if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return "", fmt.Errorf("creating destination parent: %w", err)
+	}
+	if err := rejectSymlinkComponents(root, destination); err != nil {
+		return "", err
+	}
+	if _, err := runGitText(r.MainPath, "worktree", "move", target, destination); err != nil {
+		return "", err
+	}
+	return destination, nil
+}
+
 func (r *Repository) resolveWorktreePath(branch string) (string, bool, []WorktreeInfo, error) {
 	destination, err := r.ManagedPath(branch)
 	if err != nil {
@@ -234,45 +383,118 @@ func (r *Repository) RemoveWorktree(path string, discard bool) error {
 	return nil
 }
 
-func (r *Repository) repairWorktreeGitFileIfNeeded(target string) error {
+// DeleteBranch force-deletes a local branch. Callers decide whether its work
+// is merged; git branch -d would only compare against the main checkout's HEAD.
+func (r *Repository) DeleteBranch(branch string) error {
+	if err := r.ValidateBranch(branch); err != nil {
+		return err
+	}
+	_, err := runGitText(r.MainPath, "branch", "-D", "--", branch)
+	return err
+}
+
+// RenameBranch renames a local branch. Git carries the branch's config section,
+// including Grove's own keys, to the new name; stacked branches that recorded
+// the old name as their parent are pointed at the new one.
+func (r *Repository) RenameBranch(branch, newBranch string) error {
+	if err := r.ValidateBranch(newBranch); err != nil {
+		return err
+	}
+	if r.RefExists("refs/heads/" + newBranch) {
+		return fmt.Errorf("branch %q already exists", newBranch)
+	}
+	parents, err := r.BranchParents()
+	if err != nil {
+		return err
+	}
+	if _, err := runGitText(r.MainPath, "branch", "-m", "--", branch, newBranch); err != nil {
+		return err
+	}
+	for child, parent := range parents {
+		if parent != branch {
+			continue
+		}
+		if err := r.SetBranchParent(child, newBranch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LockWorktree marks the linked worktree at path as locked, which Git and
+// Grove both honor before removing or pruning it.
+func (r *Repository) LockWorktree(path, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	_, err := runGitText(r.MainPath, append(args, "--", path)...)
+	return err
+}
+
+func (r *Repository) UnlockWorktree(path string) error {
+	_, err := runGitText(r.MainPath, "worktree", "unlock", "--", path)
+	return err
+}
+
+// WorktreeGitFileValid reports whether the linked worktree at path resolves to
+// its own administrative directory rather than a stale or borrowed one.
+func (r *Repository) WorktreeGitFileValid(path string) bool {
+	target, err := canonicalPath(path)
+	if err != nil {
+		return false
+	}
 	gitDirRaw, err := runGitText(target, "rev-parse", "--path-format=absolute", "--git-dir")
-	if err == nil {
-		gitDir, resolveErr := canonicalPath(strings.TrimSpace(gitDirRaw))
-		if resolveErr == nil && r.worktreeAdminTargets(gitDir, target) {
-			return nil
+	if err != nil {
+		return false
+	}
+	gitDir, err := canonicalPath(strings.TrimSpace(gitDirRaw))
+	return err == nil && r.worktreeAdminTargets(gitDir, target)
+}
+
+func (r *Repository) repairWorktreeGitFileIfNeeded(target string) error {
+	if r.WorktreeGitFileValid(target) {
+		return nil
+	}
+	adminDir, err := r.worktreeAdminDir(target)
+	if err != nil {
+		return fmt.Errorf("repairing worktree registration: %w", err)
+	}
+	pointer := []byte("gitdir: " + adminDir + "\n")
+	gitFile := filepath.Join(target, ".git")
+	info, err := os.Lstat(gitFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("repairing worktree registration: %w", err)
+	}
+	if err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(gitFile); err != nil {
+			return fmt.Errorf("repairing worktree registration: %w", err)
 		}
 	}
+	if err := os.WriteFile(gitFile, pointer, 0644); err != nil {
+		return fmt.Errorf("repairing worktree registration: %w", err)
+	}
+	return nil
+}
 
+// worktreeAdminDir finds the administrative directory registered for the
+// linked worktree at target without trusting the worktree's .git file.
+func (r *Repository) worktreeAdminDir(target string) (string, error) {
 	adminRoot := filepath.Join(r.CommonDir, "worktrees")
 	entries, err := os.ReadDir(adminRoot)
 	if err != nil {
-		return fmt.Errorf("repairing worktree registration: %w", err)
+		return "", err
 	}
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 		adminDir := filepath.Join(adminRoot, entry.Name())
-		if !r.worktreeAdminTargets(adminDir, target) {
-			continue
-		}
-		pointer := []byte("gitdir: " + adminDir + "\n")
-		gitFile := filepath.Join(target, ".git")
-		info, err := os.Lstat(gitFile)
-		if err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("repairing worktree registration: %w", err)
-		}
-		if err == nil && info.Mode()&os.ModeSymlink != 0 {
-			if err := os.Remove(gitFile); err != nil {
-				return fmt.Errorf("repairing worktree registration: %w", err)
-			}
+		if r.worktreeAdminTargets(adminDir, target) {
+			return adminDir, nil
 		}
-		if err := os.WriteFile(gitFile, pointer, 0644); err != nil {
-			return fmt.Errorf("repairing worktree registration: %w", err)
-		}
-		return nil
 	}
-	return fmt.Errorf("repairing worktree registration: no administrative directory found for %s", target)
+	return "", fmt.Errorf("no administrative directory found for %s", target)
 }
 
 func (r *Repository) worktreeAdminTargets(adminDir, target string) bool {
@@ -361,21 +583,64 @@ func (r *Repository) RefExists(ref string) bool {
 }
 
 func (r *Repository) BaseRef(defaultBranch string) (string, error) {
+	remote := r.Remote()
 	branch := strings.TrimPrefix(defaultBranch, "refs/heads/")
-	branch = strings.TrimPrefix(branch, "refs/remotes/origin/")
-	branch = strings.TrimPrefix(branch, "origin/")
+	branch = strings.TrimPrefix(branch, "refs/remotes/"+remote+"/")
+	branch = strings.TrimPrefix(branch, remote+"/")
 	if branch == "" {
-		branch = DefaultBranch(r.MainPath)
+		branch = DefaultBranch(r.MainPath, remote)
 	}
-	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/" + remote + "/" + branch} {
 		if r.RefExists(ref) {
 			return ref, nil
 		}
 	}
-	return "", fmt.Errorf("default branch %q does not exist locally or at origin", branch)
+	return "", fmt.Errorf("default branch %q does not exist locally or at %s", branch, remote)
+}
+
+// FetchBranch creates the local branch from a single remote ref. It never
+// forces, so an existing local branch is left for the caller to reuse. The
+// branch then tracks source on remote, so a plain git pull updates it.
+func (r *Repository) FetchBranch(remote, source, branch string) error {
+	if err := r.ValidateBranch(branch); err != nil {
+		return err
+	}
+	if _, err := runGitText(r.MainPath, "fetch", "--no-tags", "--", remote, source+":refs/heads/"+branch); err != nil {
+		return err
+	}
+	if _, err := runGitText(r.MainPath, "config", "branch."+branch+".remote", remote); err != nil {
+		return err
+	}
+	_, err := runGitText(r.MainPath, "config", "branch."+branch+".merge", source)
+	return err
 }
 
-func (r *Repository) BranchMerged(branch, defaultBranch string) (bool, string, error) {
+// ResolveStartPoint validates a user-supplied commit-ish for a new branch. A
+// name that only exists as a remote-tracking branch of a configured remote
+// resolves to it, the same way BaseRef falls back for the default branch.
+func (r *Repository) ResolveStartPoint(ref string) (string, error) {
+	if strings.TrimSpace(ref) == "" {
+		return "", fmt.Errorf("start point is required")
+	}
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid start point %q", ref)
+	}
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = r.MainPath
+	if cmd.Run() == nil {
+		return ref, nil
+	}
+	if remote, ok := r.RemoteBranch(ref); ok {
+		return remote, nil
+	}
+	return "", fmt.Errorf("start point %q does not name a commit", ref)
+}
+
+// BranchMerged reports whether branch is an ancestor of the default branch,
+// and returns the base ref it checked. With upstream, a branch merged into the
+// default branch's remote-tracking ref also counts, so callers that have just
+// fetched find branches merged upstream before the local default is pulled.
+func (r *Repository) BranchMerged(branch, defaultBranch string, upstream bool) (bool, string, error) {
 	base, err := r.BaseRef(defaultBranch)
 	if err != nil {
 		return false, "", err
@@ -384,17 +649,16 @@ func (r *Repository) BranchMerged(branch, defaultBranch string) (bool, string, e
 	if !r.RefExists(ref) {
 		return false, base, fmt.Errorf("branch %q does not exist", branch)
 	}
-	cmd := exec.Command("git", "merge-base", "--is-ancestor", ref, base)
-	cmd.Dir = r.MainPath
-	err = cmd.Run()
-	if err == nil {
-		return true, base, nil
+	merged, err := r.isAncestor(ref, base)
+	if err != nil || merged || !upstream {
+		return merged, base, err
 	}
-	var exitErr *exec.ExitError
-	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+	remote := "refs/remotes/" + r.Remote() + "/" + strings.TrimPrefix(base, "refs/heads/")
+	if remote == base || !r.RefExists(remote) {
 		return false, base, nil
 	}
-	return false, base, fmt.Errorf("checking whether %s is merged into %s: %w", branch, base, err)
+	merged, err = r.isAncestor(ref, remote)
+	return merged, base, err
 }
 
 func (r *Repository) AheadBehind(path, defaultBranch string) (int, int, error) {
@@ -402,6 +666,12 @@ func (r *Repository) AheadBehind(path, defaultBranch string) (int, int, error) {
 	if err != nil {
 		return 0, 0, err
 	}
+	return r.AheadBehindRef(path, base)
+}
+
+// AheadBehindRef compares HEAD at path with an already resolved base ref, so
+// callers checking many worktrees resolve the default branch only once.
+func (r *Repository) AheadBehindRef(path, base string) (int, int, error) {
 	out, err := runGitText(path, "rev-list", "--left-right", "--count", "HEAD..."+base)
 	if err != nil {
 		return 0, 0, err
@@ -413,16 +683,27 @@ func (r *Repository) AheadBehind(path, defaultBranch string) (int, int, error) {
 	return ahead, behind, nil
 }
 
+func (r *Repository) sharedExcludePath() string {
+	return filepath.Join(r.CommonDir, "info", "exclude")
+}
+
+func excludesManagedRoot(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "/.wt/" {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Repository) ensureSharedExclude() error {
-	path := filepath.Join(r.CommonDir, "info", "exclude")
+	path := r.sharedExcludePath()
 	data, err := os.ReadFile(path)
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("reading shared exclude: %w", err)
 	}
-	for _, line := range strings.Split(string(data), "\n") {
-		if strings.TrimSpace(line) == "/.wt/" {
-			return nil
-		}
+	if excludesManagedRoot(data) {
+		return nil
 	}
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating Git info directory: %w", err)
@@ -651,8 +932,15 @@ func runGitText(dir string, args ...string) (string, error) {
 }
 
 func runGitBytes(dir string, args ...string) ([]byte, error) {
+	return runGitBytesEnv(dir, nil, args...)
+}
+
+func runGitBytesEnv(dir string, env []string, args ...string) ([]byte, error) {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = dir
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		detail := strings.TrimSpace(string(out))
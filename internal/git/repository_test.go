@@ -413,11 +413,11 @@ func TestMergedUsesConfiguredDefaultReference(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	merged, base, err := repo.BranchMerged("feat/merged", "main")
+	merged, base, err := repo.BranchMerged("feat/merged", "main", false)
 	if err != nil || !merged || base != "refs/heads/main" {
 		t.Fatalf("BranchMerged(merged) = %v, %q, %v", merged, base, err)
 	}
-	merged, _, err = repo.BranchMerged("feat/open", "main")
+	merged, _, err = repo.BranchMerged("feat/open", "main", false)
 	if err != nil || merged {
 		t.Fatalf("BranchMerged(open) = %v, %v; want false, nil", merged, err)
 	}
@@ -445,6 +445,39 @@ func TestBaseRefPrefersTheLocalDefaultBranch(t *testing.T) {
 	}
 }
 
+func TestRemotesAreSearchedInOrder(t *testing.T) {
+	mainPath := initTestRepo(t)
+	writeCommit(t, mainPath, "base.txt", "base")
+	for _, remote := range []string{"upstream", "fork"} {
+		remotePath := filepath.Join(t.TempDir(), remote+".git")
+		runGit(t, mainPath, "init", "--bare", remotePath)
+		runGit(t, mainPath, "remote", "add", remote, remotePath)
+	}
+	runGit(t, mainPath, "push", "upstream", "main", "main:feat/shared")
+	runGit(t, mainPath, "push", "fork", "main:feat/shared", "main:feat/fork-only")
+	runGit(t, mainPath, "fetch", "upstream")
+	runGit(t, mainPath, "fetch", "fork")
+	runGit(t, mainPath, "branch", "-m", "main", "local-only")
+	repo, err := OpenRepository(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.Remotes = []string{"upstream", "fork"}
+
+	if got, err := repo.BaseRef("main"); err != nil || got != "refs/remotes/upstream/main" {
+		t.Fatalf("BaseRef() = %q, %v; want upstream main", got, err)
+	}
+	if got, ok := repo.RemoteBranch("feat/shared"); !ok || got != "refs/remotes/upstream/feat/shared" {
+		t.Fatalf("RemoteBranch(shared) = %q, %v", got, ok)
+	}
+	if _, _, err := repo.CreateWorktree("feat/fork-only", ""); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	if got := gitOutput(t, mainPath, "rev-parse", "--abbrev-ref", "feat/fork-only@{upstream}"); got != "fork/feat/fork-only" {
+		t.Fatalf("upstream = %q, want fork/feat/fork-only", got)
+	}
+}
+
 func canonicalTestPath(t *testing.T, path string) string {
 	t.Helper()
 	resolved, err := filepath.EvalSymlinks(path)
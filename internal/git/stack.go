@@ -0,0 +1,84 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+const parentConfigSuffix = ".grove-parent"
+
+// SetBranchParent records the branch a stacked branch was created from. The
+// value lives in the shared repository config so every worktree sees it, and
+// Git drops it together with the branch section when the branch is deleted.
+func (r *Repository) SetBranchParent(branch, parent string) error {
+	_, err := runGitText(r.MainPath, "config", "branch."+branch+parentConfigSuffix, parent)
+	return err
+}
+
+// BranchParents maps each stacked branch to its recorded parent branch.
+func (r *Repository) BranchParents() (map[string]string, error) {
+	parents, err := r.branchConfigValues(parentConfigSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("reading stacked branch parents: %w", err)
+	}
+	return parents, nil
+}
+
+// branchConfigValues reads one grove key from every branch.<name> section.
+func (r *Repository) branchConfigValues(suffix string) (map[string]string, error) {
+	cmd := exec.Command("git", "config", "-z", "--get-regexp", `^branch\..*`+regexp.QuoteMeta(suffix)+`$`)
+	cmd.Dir = r.MainPath
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseBranchConfig(out, suffix), nil
+}
+
+func parseBranchConfig(data []byte, suffix string) map[string]string {
+	values := make(map[string]string)
+	for _, record := range bytes.Split(data, []byte{0}) {
+		key, value, ok := strings.Cut(string(record), "\n")
+		if !ok || !strings.HasPrefix(key, "branch.") || !strings.HasSuffix(key, suffix) {
+			continue
+		}
+		branch := strings.TrimSuffix(strings.TrimPrefix(key, "branch."), suffix)
+		if branch != "" && value != "" {
+			values[branch] = value
+		}
+	}
+	return values
+}
+
+// BranchContainedIn reports whether branch's tip is reachable from parent, so a
+// stacked branch already folded into its parent carries no unique work.
+func (r *Repository) BranchContainedIn(branch, parent string) (bool, error) {
+	for _, name := range []string{branch, parent} {
+		if !r.RefExists("refs/heads/" + name) {
+			return false, fmt.Errorf("branch %q does not exist", name)
+		}
+	}
+	return r.isAncestor("refs/heads/"+branch, "refs/heads/"+parent)
+}
+
+func (r *Repository) isAncestor(ref, base string) (bool, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ref, base)
+	cmd.Dir = r.MainPath
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("checking whether %s is merged into %s: %w", strings.TrimPrefix(ref, "refs/heads/"), base, err)
+}
@@ -0,0 +1,203 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorktreeStatus summarizes one `git status --porcelain=v2 --branch` run.
+type WorktreeStatus struct {
+	Staged     int
+	Modified   int
+	Untracked  int
+	Conflicted int
+	// Operation names an unfinished rebase, merge, cherry-pick, revert, or
+	// bisect; it is empty when the worktree is idle.
+	Operation string
+	// Upstream is the branch's configured upstream. UpstreamGone reports an
+	// upstream whose ref no longer exists, in which case no counts are known.
+	Upstream       string
+	UpstreamGone   bool
+	UpstreamAhead  int
+	UpstreamBehind int
+}
+
+func (s WorktreeStatus) Dirty() bool {
+	return s.Staged+s.Modified+s.Untracked+s.Conflicted != 0
+}
+
+// Status reads file counts and upstream tracking for the worktree at path in a
+// single Git process; the in-progress operation comes from its admin files.
+func (r *Repository) Status(path string) (WorktreeStatus, error) {
+	out, err := runGitBytes(path, "status", "--porcelain=v2", "--branch", "-z", "--untracked-files=all", "--ignore-submodules=none")
+	if err != nil {
+		return WorktreeStatus{}, err
+	}
+	status, err := parseStatusPorcelainV2(out)
+	if err != nil {
+		return WorktreeStatus{}, err
+	}
+	status.Operation = worktreeOperation(path)
+	return status, nil
+}
+
+func parseStatusPorcelainV2(data []byte) (WorktreeStatus, error) {
+	var status WorktreeStatus
+	hasCounts := false
+	fields := bytes.Split(data, []byte{0})
+	for index := 0; index < len(fields); index++ {
+		record := string(fields[index])
+		if record == "" {
+			continue
+		}
+		switch record[0] {
+		case '#':
+			header := strings.TrimPrefix(record, "# ")
+			switch {
+			case strings.HasPrefix(header, "branch.upstream "):
+				status.Upstream = strings.TrimPrefix(header, "branch.upstream ")
+			case strings.HasPrefix(header, "branch.ab "):
+				counts := strings.Fields(strings.TrimPrefix(header, "branch.ab "))
+				if len(counts) != 2 {
+					return WorktreeStatus{}, fmt.Errorf("parsing upstream counts %q", header)
+				}
+				ahead, aheadErr := strconv.Atoi(strings.TrimPrefix(counts[0], "+"))
+				behind, behindErr := strconv.Atoi(strings.TrimPrefix(counts[1], "-"))
+				if aheadErr != nil || behindErr != nil {
+					return WorktreeStatus{}, fmt.Errorf("parsing upstream counts %q", header)
+				}
+				status.UpstreamAhead, status.UpstreamBehind = ahead, behind
+				hasCounts = true
+			}
+		case '1', '2':
+			if len(record) < 4 {
+				return WorktreeStatus{}, fmt.Errorf("parsing status entry %q", record)
+			}
+			if record[2] != '.' {
+				status.Staged++
+			}
+			if record[3] != '.' {
+				status.Modified++
+			}
+			if record[0] == '2' {
+				// Renames and copies carry their original path as the next field.
+				index++
+			}
+		case 'u':
+			status.Conflicted++
+		case '?':
+			status.Untracked++
+		}
+	}
+	status.UpstreamGone = status.Upstream != "" && !hasCounts
+	return status, nil
+}
+
+func worktreeOperation(path string) string {
+	gitDir, ok := worktreeGitDir(path)
+	if !ok {
+		return ""
+	}
+	for _, marker := range []struct {
+		name      string
+		operation string
+	}{
+		{name: "rebase-merge", operation: "rebase"},
+		{name: "rebase-apply", operation: "rebase"},
+		{name: "MERGE_HEAD", operation: "merge"},
+		{name: "CHERRY_PICK_HEAD", operation: "cherry-pick"},
+		{name: "REVERT_HEAD", operation: "revert"},
+		{name: "BISECT_LOG", operation: "bisect"},
+	} {
+		if _, err := os.Lstat(filepath.Join(gitDir, marker.name)); err == nil {
+			return marker.operation
+		}
+	}
+	return ""
+}
+
+// worktreeGitDir follows a worktree's .git entry without spawning Git: the main
+// worktree has a directory, and linked worktrees have a gitdir pointer file.
+func worktreeGitDir(path string) (string, bool) {
+	gitPath := filepath.Join(path, ".git")
+	info, err := os.Lstat(gitPath)
+	if err != nil {
+		return "", false
+	}
+	if info.IsDir() {
+		return gitPath, true
+	}
+	if !info.Mode().IsRegular() {
+		return "", false
+	}
+	data, err := os.ReadFile(gitPath)
+	if err != nil {
+		return "", false
+	}
+	pointer := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(pointer, "gitdir: ") {
+		return "", false
+	}
+	gitDir := strings.TrimPrefix(pointer, "gitdir: ")
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(path, gitDir)
+	}
+	return filepath.Clean(gitDir), true
+}
+
+// ChangedFiles lists the worktree's changes as `git status --short` lines,
+// such as " M main.go" or "?? notes.txt", in Git's order.
+func (r *Repository) ChangedFiles(path string) ([]string, error) {
+	out, err := runGitBytes(path, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	fields := bytes.Split(out, []byte{0})
+	changes := make([]string, 0, len(fields))
+	for index := 0; index < len(fields); index++ {
+		record := string(fields[index])
+		if len(record) < 4 {
+			continue
+		}
+		if record[0] == 'R' || record[0] == 'C' {
+			// Renames and copies carry their original path as the next field.
+			index++
+		}
+		changes = append(changes, record)
+	}
+	return changes, nil
+}
+
+// CommitSummary is one line of a worktree's history.
+type CommitSummary struct {
+	Hash    string
+	Subject string
+	At      time.Time
+}
+
+// RecentCommits returns up to count commits reachable from the worktree's
+// HEAD, newest first.
+func (r *Repository) RecentCommits(path string, count int) ([]CommitSummary, error) {
+	out, err := runGitBytes(path, "log", "-z", "-n", strconv.Itoa(count), "--format=%H%x1f%ct%x1f%s", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var commits []CommitSummary
+	for _, record := range bytes.Split(out, []byte{0}) {
+		parts := strings.SplitN(strings.TrimPrefix(string(record), "\n"), "\x1f", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		seconds, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing commit time %q", parts[1])
+		}
+		commits = append(commits, CommitSummary{Hash: parts[0], Subject: parts[2], At: time.Unix(seconds, 0)})
+	}
+	return commits, nil
+}
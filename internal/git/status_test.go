@@ -0,0 +1,119 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStatusCountsFilesAndUpstreamInOneRun(t *testing.T) {
+	mainPath := initTestRepo(t)
+	writeCommit(t, mainPath, "tracked.txt", "base")
+	writeCommit(t, mainPath, "renamed.txt", "rename me")
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, mainPath, "init", "--bare", remotePath)
+	runGit(t, mainPath, "remote", "add", "origin", remotePath)
+	runGit(t, mainPath, "push", "-u", "origin", "main")
+	writeCommit(t, mainPath, "ahead.txt", "ahead")
+	repo, err := OpenRepository(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(mainPath, "tracked.txt"), []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mainPath, "new file.txt"), []byte("untracked"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, mainPath, "mv", "renamed.txt", "moved.txt")
+
+	status, err := repo.Status(mainPath)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	want := WorktreeStatus{Staged: 1, Modified: 1, Untracked: 1, Upstream: "origin/main", UpstreamAhead: 1}
+	if status != want {
+		t.Fatalf("Status() = %#v, want %#v", status, want)
+	}
+	if !status.Dirty() {
+		t.Fatal("Dirty() = false, want true")
+	}
+}
+
+func TestStatusReportsConflictsAndMergeInProgress(t *testing.T) {
+	mainPath := initTestRepo(t)
+	writeCommit(t, mainPath, "shared.txt", "base")
+	runGit(t, mainPath, "checkout", "-b", "feat/other")
+	writeCommit(t, mainPath, "shared.txt", "other")
+	runGit(t, mainPath, "checkout", "main")
+	writeCommit(t, mainPath, "shared.txt", "main")
+	linkedPath := filepath.Join(t.TempDir(), "linked")
+	runGit(t, mainPath, "worktree", "add", "-b", "feat/merge", linkedPath, "main")
+	merge := exec.Command("git", "merge", "feat/other")
+	merge.Dir = linkedPath
+	if err := merge.Run(); err == nil {
+		t.Fatal("merge unexpectedly succeeded")
+	}
+	repo, err := OpenRepository(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := repo.Status(linkedPath)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Conflicted != 1 || status.Operation != "merge" || status.Upstream != "" {
+		t.Fatalf("Status() = %#v, want one conflict during merge", status)
+	}
+}
+
+func TestParseStatusMarksMissingUpstream(t *testing.T) {
+	status, err := parseStatusPorcelainV2([]byte("# branch.oid abc\x00# branch.head feat/x\x00# branch.upstream origin/feat/x\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.UpstreamGone || status.Upstream != "origin/feat/x" {
+		t.Fatalf("status = %#v, want gone upstream", status)
+	}
+}
+
+func TestChangedFilesAndRecentCommits(t *testing.T) {
+	mainPath := initTestRepo(t)
+	writeCommit(t, mainPath, "tracked.txt", "base")
+	writeCommit(t, mainPath, "renamed.txt", "rename me")
+	repo, err := OpenRepository(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mainPath, "tracked.txt"), []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mainPath, "new\tfile.txt"), []byte("untracked"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, mainPath, "mv", "renamed.txt", "moved.txt")
+
+	changes, err := repo.ChangedFiles(mainPath)
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	want := []string{"R  moved.txt", " M tracked.txt", "?? new\tfile.txt"}
+	if !slices.Equal(changes, want) {
+		t.Fatalf("ChangedFiles() = %q, want %q", changes, want)
+	}
+
+	commits, err := repo.RecentCommits(mainPath, 5)
+	if err != nil {
+		t.Fatalf("RecentCommits() error = %v", err)
+	}
+	if len(commits) < 2 || commits[0].Subject != "renamed.txt" || len(commits[0].Hash) != 40 || commits[0].At.IsZero() {
+		t.Fatalf("RecentCommits() = %#v", commits)
+	}
+	if commits, err := repo.RecentCommits(mainPath, 1); err != nil || len(commits) != 1 {
+		t.Fatalf("RecentCommits(1) = %#v, %v", commits, err)
+	}
+}
@@ -0,0 +1,220 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrashRefPrefix holds snapshots of worktrees removed with --discard.
+const TrashRefPrefix = "refs/grove/trash/"
+
+// detachedTrashBranch stands in for the branch of a detached worktree.
+const detachedTrashBranch = "@detached"
+
+const trashStampLayout = "20060102T150405Z"
+
+// TrashEntry is one snapshot of a discarded worktree.
+type TrashEntry struct {
+	Ref string
+	// Branch is empty for a worktree that was detached.
+	Branch string
+	// Commit holds the worktree contents; its parent is Head.
+	Commit string
+	Head   string
+	At     time.Time
+}
+
+// TrashWorktree snapshots the tracked changes and untracked, non-ignored files
+// of the worktree at path into a commit on top of its HEAD and records it
+// under refs/grove/trash. A temporary index keeps the worktree's own index
+// and the user's stashes untouched.
+func (r *Repository) TrashWorktree(path, branch string, now time.Time) (TrashEntry, error) {
+	target, err := canonicalPath(path)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	// Discard removal accepts worktrees whose .git file is stale or points at
+	// another worktree, so address the registered admin directory directly.
+	adminDir, err := r.worktreeAdminDir(target)
+	if err != nil {
+		return TrashEntry{}, fmt.Errorf("snapshotting %s: %w", path, err)
+	}
+	gitEnv := []string{"GIT_DIR=" + adminDir, "GIT_WORK_TREE=" + target}
+	head, err := runGitEnv(target, gitEnv, "rev-parse", "--verify", "HEAD^{commit}")
+	if err != nil {
+		return TrashEntry{}, fmt.Errorf("snapshotting %s: worktree has no commit", path)
+	}
+	index, err := os.CreateTemp("", "grove-trash-index-")
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	indexPath := index.Name()
+	index.Close()
+	// Git refuses an empty file as an index, so let read-tree create it.
+	os.Remove(indexPath)
+	defer os.Remove(indexPath)
+	env := append(gitEnv, "GIT_INDEX_FILE="+indexPath)
+
+	if _, err := runGitEnv(target, env, "read-tree", head); err != nil {
+		return TrashEntry{}, err
+	}
+	if _, err := runGitEnv(target, env, "add", "-A"); err != nil {
+		return TrashEntry{}, err
+	}
+	tree, err := runGitEnv(target, env, "write-tree")
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	label := branch
+	if label == "" {
+		label = "detached HEAD"
+	}
+	message := fmt.Sprintf("grove trash: %s at %s", label, path)
+	commit, err := runGitEnv(r.MainPath, identityEnv(), "commit-tree", tree, "-p", head, "-m", message)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+
+	segment := branch
+	if segment == "" {
+		segment = detachedTrashBranch
+	}
+	now = now.UTC()
+	base := TrashRefPrefix + segment + "/" + now.Format(trashStampLayout)
+	for suffix := 1; ; suffix++ {
+		ref := base
+		if suffix > 1 {
+			ref = fmt.Sprintf("%s-%d", base, suffix)
+		}
+		if r.RefExists(ref) {
+			continue
+		}
+		// An empty old value makes update-ref refuse to replace a ref.
+		if _, err := runGitText(r.MainPath, "update-ref", ref, commit, ""); err != nil {
+			return TrashEntry{}, err
+		}
+		return TrashEntry{Ref: ref, Branch: branch, Commit: commit, Head: head, At: now.Truncate(time.Second)}, nil
+	}
+}
+
+// TrashEntries lists the repository's snapshots, newest first.
+func (r *Repository) TrashEntries() ([]TrashEntry, error) {
+	out, err := runGitBytes(r.MainPath, "for-each-ref", "--format=%(refname)%00%(objectname)%00%(parent)%00%(creatordate:unix)", strings.TrimSuffix(TrashRefPrefix, "/"))
+	if err != nil {
+		return nil, err
+	}
+	entries := parseTrashEntries(out)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.After(entries[j].At)
+	})
+	return entries, nil
+}
+
+func parseTrashEntries(data []byte) []TrashEntry {
+	var entries []TrashEntry
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		fields := strings.Split(string(line), "\x00")
+		if len(fields) != 4 || !strings.HasPrefix(fields[0], TrashRefPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(fields[0], TrashRefPrefix)
+		slash := strings.LastIndex(name, "/")
+		if slash <= 0 {
+			continue
+		}
+		parents := strings.Fields(fields[2])
+		if len(parents) == 0 {
+			continue
+		}
+		entry := TrashEntry{Ref: fields[0], Branch: name[:slash], Commit: fields[1], Head: parents[0]}
+		if entry.Branch == detachedTrashBranch {
+			entry.Branch = ""
+		}
+		if seconds, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			entry.At = time.Unix(seconds, 0)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// DeleteTrash drops a snapshot; a later git gc collects its commit.
+func (r *Repository) DeleteTrash(ref string) error {
+	if !strings.HasPrefix(ref, TrashRefPrefix) {
+		return fmt.Errorf("%s is not a trash ref", ref)
+	}
+	_, err := runGitText(r.MainPath, "update-ref", "-d", ref)
+	return err
+}
+
+// RestoreTrash recreates a worktree for the snapshot's branch and writes the
+// snapshot back as uncommitted changes. The branch is recreated at the
+// snapshot's HEAD when it was deleted; a branch that has moved on is refused
+// so restored files never land on top of unrelated commits. A detached
+// snapshot is restored onto a new restored/<timestamp> branch. The trash ref
+// is left for the caller to delete.
+func (r *Repository) RestoreTrash(entry TrashEntry) (string, string, error) {
+	branch := entry.Branch
+	if branch == "" {
+		branch = "restored/" + entry.At.UTC().Format(trashStampLayout)
+	}
+	if err := r.ValidateBranch(branch); err != nil {
+		return "", "", err
+	}
+	path, existing, _, err := r.resolveWorktreePath(branch)
+	if err != nil {
+		return "", "", err
+	}
+	if existing {
+		return "", "", fmt.Errorf("branch %q is already checked out at %s", branch, path)
+	}
+	if r.RefExists("refs/heads/" + branch) {
+		tip, err := runGitText(r.MainPath, "rev-parse", "--verify", "refs/heads/"+branch)
+		if err != nil {
+			return "", "", err
+		}
+		if tip != entry.Head {
+			return "", "", fmt.Errorf("branch %q has moved since the snapshot was taken (%s, was %s)", branch, shortCommit(tip), shortCommit(entry.Head))
+		}
+	} else if _, err := runGitText(r.MainPath, "branch", branch, entry.Head); err != nil {
+		return "", "", err
+	}
+	path, _, err = r.CreateWorktree(branch, "")
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := runGitText(path, "read-tree", "-u", "--reset", entry.Commit); err != nil {
+		return path, branch, err
+	}
+	// Leave the restored files uncommitted, as they were before removal.
+	if _, err := runGitText(path, "reset", "-q"); err != nil {
+		return path, branch, err
+	}
+	return path, branch, nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+// identityEnv supplies a fixed identity for commits Grove writes on its own
+// behalf, so they work without user.name configured.
+func identityEnv() []string {
+	return []string{
+		"GIT_AUTHOR_NAME=grove", "GIT_AUTHOR_EMAIL=grove@localhost",
+		"GIT_COMMITTER_NAME=grove", "GIT_COMMITTER_EMAIL=grove@localhost",
+	}
+}
+
+func runGitEnv(dir string, env []string, args ...string) (string, error) {
+	out, err := runGitBytesEnv(dir, env, args...)
+	return strings.TrimSpace(string(out)), err
+}
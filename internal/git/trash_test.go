@@ -0,0 +1,113 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrashWorktreeRoundTripsUncommittedFiles(t *testing.T) {
+	mainPath := initTestRepo(t)
+	writeCommit(t, mainPath, ".gitignore", "build/\n")
+	writeCommit(t, mainPath, "tracked.txt", "base")
+	repo, err := OpenRepository(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _, err := repo.CreateWorktree("feat/trash", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		target := filepath.Join(path, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("tracked.txt", "edited")
+	writeFile("notes/new.txt", "untracked")
+	writeFile("build/out.bin", "ignored")
+	runGit(t, path, "add", "tracked.txt")
+	head := gitOutput(t, path, "rev-parse", "HEAD")
+
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	entry, err := repo.TrashWorktree(path, "feat/trash", at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := TrashRefPrefix + "feat/trash/20260304T050607Z"; entry.Ref != want || entry.Head != head {
+		t.Fatalf("TrashWorktree() = %+v, want ref %s on %s", entry, want, head)
+	}
+	if staged := gitOutput(t, path, "diff", "--cached", "--name-only"); staged != "tracked.txt" {
+		t.Fatalf("snapshot changed the worktree index: staged %q", staged)
+	}
+	again, err := repo.TrashWorktree(path, "feat/trash", at)
+	if err != nil || again.Ref != entry.Ref+"-2" {
+		t.Fatalf("second snapshot = %q, %v; want a suffixed ref", again.Ref, err)
+	}
+	if err := repo.DeleteTrash(again.Ref); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.RemoveWorktree(path, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.DeleteBranch("feat/trash"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := repo.TrashEntries()
+	if err != nil || len(entries) != 1 || entries[0].Branch != "feat/trash" || entries[0].Head != head {
+		t.Fatalf("TrashEntries() = %+v, %v", entries, err)
+	}
+
+	restored, branch, err := repo.RestoreTrash(entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if branch != "feat/trash" || gitOutput(t, restored, "rev-parse", "HEAD") != head {
+		t.Fatalf("RestoreTrash() restored %s at %s", branch, gitOutput(t, restored, "rev-parse", "HEAD"))
+	}
+	for name, want := range map[string]string{"tracked.txt": "edited", "notes/new.txt": "untracked"} {
+		if data, err := os.ReadFile(filepath.Join(restored, name)); err != nil || string(data) != want {
+			t.Fatalf("%s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(restored, "build", "out.bin")); !os.IsNotExist(err) {
+		t.Fatalf("ignored file was snapshotted: %v", err)
+	}
+	if status := gitOutput(t, restored, "status", "--porcelain"); status != "M tracked.txt\n?? notes/" {
+		t.Fatalf("restored status = %q", status)
+	}
+}
+
+func TestRestoreTrashRefusesMovedBranch(t *testing.T) {
+	mainPath := initTestRepo(t)
+	writeCommit(t, mainPath, "base.txt", "base")
+	repo, err := OpenRepository(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _, err := repo.CreateWorktree("feat/moved", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := repo.TrashWorktree(path, "feat/moved", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RemoveWorktree(path, true); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, mainPath, "checkout", "feat/moved")
+	writeCommit(t, mainPath, "later.txt", "later")
+	runGit(t, mainPath, "checkout", "main")
+
+	if _, _, err := repo.RestoreTrash(entry); err == nil {
+		t.Fatal("RestoreTrash() succeeded onto a branch that moved")
+	}
+}
@@ -19,8 +19,13 @@ type WorktreeInfo struct {
 	Main           bool
 }
 
-func DefaultBranch(repoPath string) string {
-	if branch := remoteHeadBranch(repoPath); branch != "" {
+// DefaultBranch guesses a repository's default branch from the remote's HEAD,
+// then from common local names, then from the current branch.
+func DefaultBranch(repoPath, remote string) string {
+	if remote == "" {
+		remote = DefaultRemote
+	}
+	if branch := remoteHeadBranch(repoPath, remote); branch != "" {
 		return branch
 	}
 	for _, branch := range []string{"main", "master"} {
@@ -47,14 +52,14 @@ func ListWorktrees(repoPath string) ([]WorktreeInfo, error) {
 	return parseWorktreesPorcelain(out), nil
 }
 
-func remoteHeadBranch(repoPath string) string {
-	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+func remoteHeadBranch(repoPath, remote string) string {
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
 	cmd.Dir = repoPath
 	out, err := cmd.Output()
 	if err != nil {
 		return ""
 	}
-	return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/")
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), remote+"/")
 }
 
 func refExists(repoPath, ref string) bool {
@@ -0,0 +1,137 @@
+package inventory
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"grove/internal/catalog"
+)
+
+// FuzzyPrefix marks a selector as a fuzzy query. Git forbids "~" in ref
+// names, so no exact branch selector can start with it; "~" alone and "~/"
+// remain home-relative paths.
+const FuzzyPrefix = "~"
+
+// IsFuzzySelector reports whether selector uses the ~query syntax.
+func IsFuzzySelector(selector string) bool {
+	return strings.HasPrefix(selector, FuzzyPrefix) && !isPathSelector(selector)
+}
+
+// Fuzzy turns a selector into a fuzzy query, as --fuzzy does, leaving paths
+// and selectors that are already fuzzy alone.
+func Fuzzy(selector string) string {
+	if strings.TrimSpace(selector) == "" || isPathSelector(selector) || IsFuzzySelector(selector) {
+		return selector
+	}
+	return FuzzyPrefix + selector
+}
+
+// AmbiguousError reports a fuzzy query that matched several worktrees, all
+// equally well.
+type AmbiguousError struct {
+	Query   string
+	Matches []*Entry
+}
+
+func (e *AmbiguousError) Error() string {
+	selectors := make([]string, 0, len(e.Matches))
+	for _, match := range e.Matches {
+		selectors = append(selectors, match.Selector())
+	}
+	return fmt.Sprintf("%q matches %d worktrees: %s; use an exact selector", e.Query, len(e.Matches), strings.Join(selectors, ", "))
+}
+
+// fuzzy match strengths, strongest first. Only the strongest level with any
+// match counts, so an exact branch is never ambiguous with its substrings.
+const (
+	matchExact = iota
+	matchComponent
+	matchSubstring
+	matchNone
+)
+
+// ResolveFuzzy resolves a loose query: a branch name, a trailing part of a
+// branch such as auth for feat/auth, a component of a branch or the worktree
+// directory name, or finally any substring of those, ignoring case. A repo:
+// prefix limits the search to that repository; otherwise the current
+// repository is searched first and every repository after it when nothing
+// matches there.
+func (i *Inventory) ResolveFuzzy(query string) (*Entry, error) {
+	query = strings.TrimPrefix(query, FuzzyPrefix)
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("fuzzy selector needs a query after %q", FuzzyPrefix)
+	}
+	var scopes [][]*catalog.Repository
+	if repositoryName, rest, ok := strings.Cut(query, ":"); ok && repositoryName != "" {
+		repository, _, err := i.Catalog.FindRepository(repositoryName)
+		if err != nil {
+			return nil, err
+		}
+		if rest == "" {
+			return i.resolveInRepository(repository, "")
+		}
+		query = rest
+		scopes = append(scopes, []*catalog.Repository{repository})
+	} else {
+		if i.Catalog.Current != nil {
+			scopes = append(scopes, []*catalog.Repository{i.Catalog.Current})
+		}
+		scopes = append(scopes, i.Catalog.Repositories)
+	}
+	for _, repositories := range scopes {
+		matches := i.fuzzyMatches(repositories, query)
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			return nil, &AmbiguousError{Query: FuzzyPrefix + query, Matches: matches}
+		}
+	}
+	return nil, fmt.Errorf("no worktree matches %q", FuzzyPrefix+query)
+}
+
+// fuzzyMatches returns the worktrees of repositories at the strongest match
+// level any of them reaches, in inventory order.
+func (i *Inventory) fuzzyMatches(repositories []*catalog.Repository, query string) []*Entry {
+	best := matchNone
+	var matches []*Entry
+	for _, repository := range repositories {
+		for _, entry := range i.byRepo[repository] {
+			if entry.Worktree.Prunable {
+				continue
+			}
+			level := fuzzyLevel(entry, query)
+			switch {
+			case level < best:
+				best, matches = level, []*Entry{entry}
+			case level == best && level != matchNone:
+				matches = append(matches, entry)
+			}
+		}
+	}
+	return matches
+}
+
+func fuzzyLevel(entry *Entry, query string) int {
+	branch := entry.Worktree.Branch
+	directory := filepath.Base(entry.Worktree.Path)
+	if branch == query {
+		return matchExact
+	}
+	if branch != "" && strings.HasSuffix(branch, "/"+query) || directory == query {
+		return matchComponent
+	}
+	for _, component := range strings.Split(branch, "/") {
+		if component == query {
+			return matchComponent
+		}
+	}
+	lower := strings.ToLower(query)
+	if strings.Contains(strings.ToLower(branch), lower) || strings.Contains(strings.ToLower(directory), lower) {
+		return matchSubstring
+	}
+	return matchNone
+}
@@ -62,6 +62,9 @@ func (i *Inventory) Resolve(selector, baseDir string) (*Entry, error) {
 	if strings.TrimSpace(selector) == "" {
 		return nil, fmt.Errorf("worktree selector is required")
 	}
+	if IsFuzzySelector(selector) {
+		return i.ResolveFuzzy(selector)
+	}
 	if isPathSelector(selector) {
 		return i.resolvePath(selector, baseDir)
 	}
@@ -1,6 +1,7 @@
 package inventory
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -119,6 +120,44 @@ func TestResolveBareBranchRequiresCurrentRepository(t *testing.T) {
 	}
 }
 
+func TestResolveFuzzyPrefersStrongerMatchesAndReportsAmbiguity(t *testing.T) {
+	repoPath := initInventoryRepo(t)
+	otherPath := initInventoryRepo(t)
+	base := t.TempDir()
+	runInventoryGit(t, repoPath, "worktree", "add", "-b", "feat/auth", filepath.Join(base, "one"))
+	runInventoryGit(t, repoPath, "worktree", "add", "-b", "fix/auth-ui", filepath.Join(base, "two"))
+	runInventoryGit(t, repoPath, "worktree", "add", "-b", "fix/login", filepath.Join(base, "three"))
+	runInventoryGit(t, otherPath, "worktree", "add", "-b", "feat/billing", filepath.Join(base, "four"))
+	cat, _ := catalog.Build(&config.Config{Repos: []config.RepoConfig{{Path: repoPath, Name: "app"}, {Path: otherPath, Name: "web"}}}, repoPath)
+	inv, _ := Build(cat)
+
+	for selector, want := range map[string]string{
+		"~auth":     "app:feat/auth", // a whole component beats the substring in fix/auth-ui
+		"~LOG":      "app:fix/login",
+		"~three":    "app:fix/login", // the worktree directory name
+		"~bill":     "web:feat/billing",
+		"~web:feat": "web:feat/billing",
+		"~web:":     "web:",
+	} {
+		got, err := inv.Resolve(selector, repoPath)
+		if err != nil || got.Selector() != want {
+			t.Fatalf("Resolve(%s) = %v, %v; want %s", selector, got, err, want)
+		}
+	}
+
+	_, err := inv.Resolve("~fix", repoPath)
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Matches) != 2 || !strings.Contains(err.Error(), "app:fix/auth-ui") || !strings.Contains(err.Error(), "app:fix/login") {
+		t.Fatalf("Resolve(~fix) error = %v, want both fix worktrees", err)
+	}
+	if _, err := inv.Resolve("~nothing", repoPath); err == nil || !strings.Contains(err.Error(), "no worktree matches") {
+		t.Fatalf("Resolve(~nothing) error = %v", err)
+	}
+	if _, err := inv.Resolve("auth", repoPath); err == nil {
+		t.Fatal("exact selector auth matched feat/auth")
+	}
+}
+
 func initInventoryRepo(t *testing.T) string {
 	t.Helper()
 	path := t.TempDir()
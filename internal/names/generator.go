@@ -3,6 +3,8 @@ package names
 import (
 	"fmt"
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
 	_ "time/tzdata"
 )
@@ -82,6 +84,24 @@ func GenerateBranch(existing []string) string {
 	return generateBranchAt(existing, time.Now())
 }
 
+// IsGeneratedBranch reports whether branch has the shape GenerateBranch
+// produces, including the numeric suffix added after exhaustion.
+func IsGeneratedBranch(branch string) bool {
+	rest, ok := strings.CutPrefix(branch, "feat/")
+	if !ok || len(rest) < len("01-02-") {
+		return false
+	}
+	if _, err := time.Parse("01-02", rest[:5]); err != nil || rest[5] != '-' {
+		return false
+	}
+	adjective, animal, ok := strings.Cut(rest[6:], "-")
+	if !ok || !slices.Contains(warmAdjectives, adjective) {
+		return false
+	}
+	animal = strings.TrimRightFunc(animal, func(r rune) bool { return r >= '0' && r <= '9' })
+	return slices.Contains(cuteAnimals, animal)
+}
+
 func generateBranchAt(existing []string, now time.Time) string {
 	stamp := now.In(pacific()).Format("01-02")
 	candidates := make([]string, 0, len(warmAdjectives)*len(cuteAnimals))
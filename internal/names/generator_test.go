@@ -81,3 +81,21 @@ func contains(values []string, target string) bool {
 	}
 	return false
 }
+
+func TestIsGeneratedBranch(t *testing.T) {
+	now := time.Date(2026, time.July, 30, 12, 0, 0, 0, time.UTC)
+	if branch := generateBranchAt(nil, now); !IsGeneratedBranch(branch) {
+		t.Fatalf("IsGeneratedBranch(%q) = false", branch)
+	}
+	for branch, want := range map[string]bool{
+		"feat/07-30-cozy-otter2": true,
+		"feat/13-40-cozy-otter":  false,
+		"feat/07-30-cozy-dragon": false,
+		"feat/auth":              false,
+		"fix/07-30-cozy-otter":   false,
+	} {
+		if got := IsGeneratedBranch(branch); got != want {
+			t.Fatalf("IsGeneratedBranch(%q) = %v, want %v", branch, got, want)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package picker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// maxBuiltinRows caps the list height so the built-in picker stays inline
+// below the prompt instead of taking over the whole screen.
+const maxBuiltinRows = 15
+
+// builtinKey is one decoded keystroke.
+type builtinKey struct {
+	name string // "rune", "enter", "up", "down", "tab", "shift-tab", "backspace", "clear", "word", "cancel"
+	r    rune
+	// ctrl is fzf's name for a control key, such as ctrl-n, so that actions
+	// bound to it take precedence over its default editing behavior.
+	ctrl string
+}
+
+// controlKeys gives control bytes their default behavior. Ctrl-M and Ctrl-I
+// are Enter and Tab.
+var controlKeys = map[byte]string{
+	'\r': "enter",
+	'\t': "tab",
+	0x03: "cancel", // Ctrl-C
+	0x07: "cancel", // Ctrl-G
+	0x04: "cancel", // Ctrl-D
+	0x08: "backspace",
+	0x15: "clear", // Ctrl-U
+	0x17: "word",  // Ctrl-W
+	0x10: "up",    // Ctrl-P
+	0x0b: "up",    // Ctrl-K
+	0x0e: "down",  // Ctrl-N
+	'\n': "down",  // Ctrl-J
+}
+
+// builtinPicker is the state of the pure-Go picker. It filters like fzf with
+// --no-sort: every space-separated term must match as a subsequence, and
+// matches keep the caller's order.
+type builtinPicker struct {
+	prompt   string
+	items    []Item
+	labels   []string
+	multi    bool
+	actions  []Action
+	query    []rune
+	matches  []int
+	cursor   int
+	offset   int
+	selected []int
+}
+
+func newBuiltinPicker(prompt string, items []Item, multi bool, actions []Action) *builtinPicker {
+	p := &builtinPicker{prompt: prompt, items: items, multi: multi, actions: actions}
+	replacer := strings.NewReplacer("\x00", " ", "\n", " ", "\r", " ", "\t", " ", "\x1b", " ")
+	for _, item := range items {
+		p.labels = append(p.labels, replacer.Replace(item.Label))
+	}
+	p.filter()
+	return p
+}
+
+// selectBuiltin runs the picker on a terminal: keys are read from in, which
+// is put into raw mode, and the list is drawn on out.
+func selectBuiltin(in *os.File, out io.Writer, prompt string, items []Item, multi bool, actions []Action) (selection, error) {
+	fd := int(in.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return selection{}, fmt.Errorf("built-in picker needs a terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+	width, height, err := term.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	p := newBuiltinPicker(prompt, items, multi, actions)
+	rows := min(maxBuiltinRows, max(height-2, 1))
+	p.draw(out, width, rows)
+	defer func() {
+		// Leave the terminal as it was: erase the picker from the prompt line down.
+		fmt.Fprint(out, "\r\x1b[J")
+	}()
+
+	buffer := make([]byte, 256)
+	for {
+		n, err := in.Read(buffer)
+		if err != nil {
+			return selection{}, ErrCancelled
+		}
+		for _, key := range decodeKeys(buffer[:n]) {
+			done, result, err := p.handle(key)
+			if err != nil {
+				return selection{}, err
+			}
+			if done {
+				return result, nil
+			}
+		}
+		p.draw(out, width, rows)
+	}
+}
+
+// handle applies one key, reporting the result once the user confirms or
+// presses an action key.
+func (p *builtinPicker) handle(key builtinKey) (bool, selection, error) {
+	for _, action := range p.actions {
+		if key.ctrl != "" && action.Key == key.ctrl {
+			return true, selection{keys: p.chosen(), action: action.Name, query: string(p.query)}, nil
+		}
+	}
+	switch key.name {
+	case "cancel":
+		return false, selection{}, ErrCancelled
+	case "enter":
+		keys := p.chosen()
+		if len(keys) == 0 {
+			return false, selection{}, ErrCancelled
+		}
+		return true, selection{keys: keys, query: string(p.query)}, nil
+	case "up":
+		p.move(-1)
+	case "down":
+		p.move(1)
+	case "tab", "shift-tab":
+		if !p.multi {
+			if key.name == "tab" {
+				p.move(1)
+			} else {
+				p.move(-1)
+			}
+			break
+		}
+		p.toggle()
+		if key.name == "tab" {
+			p.move(1)
+		} else {
+			p.move(-1)
+		}
+	case "backspace":
+		if len(p.query) != 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+	case "clear":
+		p.query = p.query[:0]
+		p.filter()
+	case "word":
+		trimmed := strings.TrimRightFunc(string(p.query), unicode.IsSpace)
+		cut := strings.LastIndexFunc(trimmed, unicode.IsSpace) + 1
+		p.query = []rune(trimmed[:cut])
+		p.filter()
+	case "rune":
+		p.query = append(p.query, key.r)
+		p.filter()
+	}
+	return false, selection{}, nil
+}
+
+// chosen returns the marked items in the order they were marked, or the item
+// under the cursor when nothing is marked.
+func (p *builtinPicker) chosen() []string {
+	if len(p.selected) != 0 {
+		keys := make([]string, 0, len(p.selected))
+		for _, index := range p.selected {
+			keys = append(keys, p.items[index].Key)
+		}
+		return keys
+	}
+	if len(p.matches) == 0 {
+		return nil
+	}
+	return []string{p.items[p.matches[p.cursor]].Key}
+}
+
+func (p *builtinPicker) move(delta int) {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.cursor = min(max(p.cursor+delta, 0), len(p.matches)-1)
+}
+
+func (p *builtinPicker) toggle() {
+	if len(p.matches) == 0 {
+		return
+	}
+	index := p.matches[p.cursor]
+	for position, selected := range p.selected {
+		if selected == index {
+			p.selected = append(p.selected[:position], p.selected[position+1:]...)
+			return
+		}
+	}
+	p.selected = append(p.selected, index)
+}
+
+func (p *builtinPicker) isSelected(index int) bool {
+	for _, selected := range p.selected {
+		if selected == index {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *builtinPicker) filter() {
+	p.matches = filterLabels(p.labels, string(p.query))
+	p.cursor, p.offset = 0, 0
+}
+
+// filterLabels returns the indexes of labels matching every term of query, in
+// their original order. Matching ignores case unless a term has an upper-case
+// letter, as fzf's smart case does.
+func filterLabels(labels []string, query string) []int {
+	terms := strings.Fields(query)
+	matches := make([]int, 0, len(labels))
+	for index, label := range labels {
+		matched := true
+		for _, term := range terms {
+			if !fuzzyMatch(label, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, index)
+		}
+	}
+	return matches
+}
+
+func fuzzyMatch(label, term string) bool {
+	if strings.IndexFunc(term, unicode.IsUpper) < 0 {
+		label = strings.ToLower(label)
+	}
+	remaining := []rune(term)
+	for _, r := range label {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// draw repaints the picker from the prompt line, where the cursor rests
+// between draws.
+func (p *builtinPicker) draw(out io.Writer, width, rows int) {
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+rows {
+		p.offset = p.cursor - rows + 1
+	}
+	var screen bytes.Buffer
+	screen.WriteString("\r\x1b[J")
+	lines := 0
+	if p.multi {
+		screen.WriteString("\r\n" + fit("  tab/shift-tab select · enter confirm", width))
+		lines++
+	} else if len(p.actions) != 0 {
+		screen.WriteString("\r\n" + fit("  "+actionHeader(p.actions), width))
+		lines++
+	}
+	status := fmt.Sprintf("  %d/%d", len(p.matches), len(p.items))
+	if len(p.selected) != 0 {
+		status += fmt.Sprintf(" (%d)", len(p.selected))
+	}
+	screen.WriteString("\r\n" + fit(status, width))
+	lines++
+	for row := p.offset; row < len(p.matches) && row < p.offset+rows; row++ {
+		index := p.matches[row]
+		marker := "  "
+		if p.isSelected(index) {
+			marker = " +"
+		}
+		line := marker + " " + p.labels[index]
+		if row == p.cursor {
+			line = ">" + line[1:]
+			screen.WriteString("\r\n\x1b[7m" + fit(line, width) + "\x1b[0m")
+		} else {
+			screen.WriteString("\r\n" + fit(line, width))
+		}
+		lines++
+	}
+	promptLine := fit(p.prompt+string(p.query), width)
+	fmt.Fprintf(&screen, "\x1b[%dA\r%s", lines, promptLine)
+	out.Write(screen.Bytes())
+}
+
+// fit truncates s to width terminal columns, counting one column per rune.
+func fit(s string, width int) string {
+	if utf8.RuneCountInString(s) < width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max(width-1, 0)])
+}
+
+// decodeKeys splits one read from the terminal into keystrokes. A lone Esc
+// cancels; escape sequences for the arrow keys and Shift-Tab move.
+func decodeKeys(input []byte) []builtinKey {
+	var keys []builtinKey
+	for len(input) != 0 {
+		switch b := input[0]; {
+		case b == 0x1b:
+			if len(input) >= 3 && (input[1] == '[' || input[1] == 'O') {
+				switch input[2] {
+				case 'A':
+					keys = append(keys, builtinKey{name: "up"})
+				case 'B':
+					keys = append(keys, builtinKey{name: "down"})
+				case 'Z':
+					keys = append(keys, builtinKey{name: "shift-tab"})
+				}
+				// Skip the rest of a longer sequence such as ESC [ 1 ; 5 A.
+				end := 2
+				for end < len(input) && (input[end] < 0x40 || input[end] > 0x7e) {
+					end++
+				}
+				input = input[min(end+1, len(input)):]
+				continue
+			}
+			keys = append(keys, builtinKey{name: "cancel"})
+			input = input[1:]
+		case b < 0x20:
+			key := builtinKey{name: controlKeys[b]}
+			if b >= 0x01 && b <= 0x1a {
+				key.ctrl = "ctrl-" + string(rune('a'+b-1))
+			}
+			keys = append(keys, key)
+			input = input[1:]
+		case b == 0x7f:
+			keys = append(keys, builtinKey{name: "backspace"})
+			input = input[1:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			if r != utf8.RuneError && unicode.IsPrint(r) {
+				keys = append(keys, builtinKey{name: "rune", r: r})
+			}
+			input = input[size:]
+		}
+	}
+	return keys
+}
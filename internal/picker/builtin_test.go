@@ -0,0 +1,135 @@
+package picker
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// runScripted drives the built-in picker on a pseudo-terminal, typing each
+// chunk of keys as a separate read.
+func runScripted(t *testing.T, items []Item, multi bool, keys ...string) ([]string, error) {
+	t.Helper()
+	result, err := runScriptedActions(t, items, multi, nil, keys...)
+	return result.keys, err
+}
+
+func runScriptedActions(t *testing.T, items []Item, multi bool, actions []Action, keys ...string) (selection, error) {
+	t.Helper()
+	ptmx, tty, err := pty.Open()
+	if err != nil {
+		t.Skipf("pseudo-terminal unavailable: %v", err)
+	}
+	defer ptmx.Close()
+	defer tty.Close()
+	go io.Copy(io.Discard, ptmx)
+
+	type outcome struct {
+		result selection
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := selectBuiltin(tty, tty, "pick > ", items, multi, actions)
+		done <- outcome{result, err}
+	}()
+	for _, chunk := range keys {
+		// Separate reads keep a lone Esc apart from the keys around it.
+		time.Sleep(20 * time.Millisecond)
+		if _, err := ptmx.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case outcome := <-done:
+		return outcome.result, outcome.err
+	case <-time.After(5 * time.Second):
+		t.Fatal("built-in picker did not finish")
+		return selection{}, nil
+	}
+}
+
+func TestBuiltinPickerFiltersInCallerOrder(t *testing.T) {
+	items := []Item{
+		{Key: "/wt/one", Label: "app:feat/alpha-one"},
+		{Key: "/wt/two", Label: "app:fix/beta"},
+		{Key: "/wt/three", Label: "app:feat/alpha-three"},
+	}
+	got, err := runScripted(t, items, false, "alp", "\x1b[B", "\r")
+	if err != nil || !slices.Equal(got, []string{"/wt/three"}) {
+		t.Fatalf("select = %#v, %v", got, err)
+	}
+	got, err = runScripted(t, items, false, "zzz", "\x15", "bet", "\r")
+	if err != nil || !slices.Equal(got, []string{"/wt/two"}) {
+		t.Fatalf("select after clearing = %#v, %v", got, err)
+	}
+}
+
+func TestBuiltinPickerMultiSelectsWithTab(t *testing.T) {
+	items := []Item{{Key: "a", Label: "one"}, {Key: "b", Label: "two"}, {Key: "c", Label: "three"}}
+	got, err := runScripted(t, items, true, "\x1b[B", "\t", "\x1b[A\x1b[A", "\t", "\r")
+	if err != nil || !slices.Equal(got, []string{"b", "a"}) {
+		t.Fatalf("multi select = %#v, %v", got, err)
+	}
+}
+
+func TestBuiltinPickerCancels(t *testing.T) {
+	items := []Item{{Key: "a", Label: "one"}}
+	for name, key := range map[string]string{"esc": "\x1b", "ctrl-c": "\x03", "no match": "zzz\r"} {
+		if _, err := runScripted(t, items, false, key); !errors.Is(err, ErrCancelled) {
+			t.Fatalf("%s: error = %v, want ErrCancelled", name, err)
+		}
+	}
+}
+
+func TestBuiltinPickerActionKeysOverrideDefaults(t *testing.T) {
+	items := []Item{{Key: "a", Label: "one"}, {Key: "b", Label: "two"}}
+	actions := []Action{{Key: "ctrl-n", Name: "new"}, {Key: "ctrl-x", Name: "remove"}}
+	result, err := runScriptedActions(t, items, false, actions, "\x1b[B", "\x18")
+	if err != nil || result.action != "remove" || !slices.Equal(result.keys, []string{"b"}) {
+		t.Fatalf("ctrl-x = %#v, %v", result, err)
+	}
+	result, err = runScriptedActions(t, items, false, actions, "fresh", "\x0e")
+	if err != nil || result.action != "new" || result.query != "fresh" || len(result.keys) != 0 {
+		t.Fatalf("ctrl-n = %#v, %v", result, err)
+	}
+	// Without actions, Ctrl-N keeps moving down.
+	got, err := runScripted(t, items, false, "\x0e", "\r")
+	if err != nil || !slices.Equal(got, []string{"b"}) {
+		t.Fatalf("ctrl-n down = %#v, %v", got, err)
+	}
+}
+
+func TestFilterLabelsUsesSmartCaseSubsequences(t *testing.T) {
+	labels := []string{"app:feat/Auth", "app:fix/auth-ui", "web:feat/login"}
+	if got := filterLabels(labels, "fa"); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("fa = %v", got)
+	}
+	if got := filterLabels(labels, "A"); !slices.Equal(got, []int{0}) {
+		t.Fatalf("A = %v", got)
+	}
+	if got := filterLabels(labels, "app auth"); !slices.Equal(got, []int{0, 1}) {
+		t.Fatalf("app auth = %v", got)
+	}
+}
+
+func TestBackendReadsGrovePicker(t *testing.T) {
+	t.Setenv("GROVE_PICKER", "builtin")
+	if backend, err := Backend(); err != nil || backend != PickerBuiltin {
+		t.Fatalf("builtin = %q, %v", backend, err)
+	}
+	t.Setenv("GROVE_PICKER", "auto")
+	t.Setenv("PATH", t.TempDir())
+	if backend, err := Backend(); err != nil || backend != PickerBuiltin {
+		t.Fatalf("auto without fzf = %q, %v", backend, err)
+	}
+	t.Setenv("GROVE_PICKER", "skim")
+	if _, err := Backend(); err == nil || !strings.Contains(err.Error(), "GROVE_PICKER") {
+		t.Fatalf("invalid GROVE_PICKER error = %v", err)
+	}
+}
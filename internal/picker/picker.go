@@ -17,38 +17,140 @@ type Item struct {
 	Label string
 }
 
-func Select(prompt string, items []Item) (string, error) {
-	selected, err := selectItems(prompt, items, false)
+// Action binds a key to a named action in the single-item picker. Keys use
+// fzf's names; the built-in picker supports ctrl-a through ctrl-z.
+type Action struct {
+	Key  string
+	Name string
+}
+
+// Selection is how the user left the picker. Action is empty when they
+// pressed Enter, and Key is empty when an action key was pressed with no item
+// matching Query, the text typed at that moment.
+type Selection struct {
+	Key    string
+	Action string
+	Query  string
+}
+
+// selection is a picker backend's result before Select and SelectMany
+// narrow it to their own shapes.
+type selection struct {
+	keys   []string
+	action string
+	query  string
+}
+
+// Select picks one item. Preview shows each highlighted item in fzf's preview
+// pane through PreviewCommand, so only pickers keyed by worktree paths or
+// selectors may set it.
+func Select(prompt string, items []Item, preview bool, actions ...Action) (Selection, error) {
+	result, err := selectItems(prompt, items, false, preview, actions)
+	if err != nil {
+		return Selection{}, err
+	}
+	chosen := Selection{Action: result.action, Query: result.query}
+	if len(result.keys) != 0 {
+		chosen.Key = result.keys[0]
+	}
+	return chosen, nil
+}
+
+// SelectMany picks any number of items; preview is as for Select.
+func SelectMany(prompt string, items []Item, preview bool) ([]string, error) {
+	result, err := selectItems(prompt, items, true, preview, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return selected[0], nil
+	return result.keys, nil
 }
 
-func SelectMany(prompt string, items []Item) ([]string, error) {
-	return selectItems(prompt, items, true)
+// Picker names accepted by GROVE_PICKER. Auto, the default, uses fzf when it
+// is installed and the built-in picker otherwise.
+const (
+	PickerAuto    = "auto"
+	PickerFZF     = "fzf"
+	PickerBuiltin = "builtin"
+)
+
+// Backend resolves GROVE_PICKER to the picker that will run.
+func Backend() (string, error) {
+	switch value := os.Getenv("GROVE_PICKER"); value {
+	case "", PickerAuto:
+		if _, err := exec.LookPath("fzf"); err != nil {
+			return PickerBuiltin, nil
+		}
+		return PickerFZF, nil
+	case PickerFZF, PickerBuiltin:
+		return value, nil
+	default:
+		return "", fmt.Errorf("GROVE_PICKER must be fzf, builtin, or auto, got %q", value)
+	}
 }
 
-func selectItems(prompt string, items []Item, multi bool) ([]string, error) {
+func selectItems(prompt string, items []Item, multi, preview bool, actions []Action) (selection, error) {
 	if len(items) == 0 {
-		return nil, fmt.Errorf("no worktrees")
+		return selection{}, fmt.Errorf("no worktrees")
 	}
-	args := selectionArgs(prompt, multi)
+	backend, err := Backend()
+	if err != nil {
+		return selection{}, err
+	}
+	if backend == PickerBuiltin {
+		return selectBuiltin(os.Stdin, os.Stderr, prompt, items, multi, actions)
+	}
+	return selectFZF(prompt, items, multi, preview, actions)
+}
+
+func selectFZF(prompt string, items []Item, multi, preview bool, actions []Action) (selection, error) {
+	args, cleanup := fzfArgs(prompt, items, multi, preview, actions)
 	cmd := exec.Command("fzf", args...)
+	if cleanup != nil {
+		defer cleanup()
+		// fzf runs the preview with $SHELL; pin a POSIX shell so the quoting
+		// below holds for users of fish and other shells.
+		cmd.Env = append(os.Environ(), "SHELL=/bin/sh")
+	}
 	cmd.Stdin = bytes.NewReader(encodeItems(items))
 	cmd.Stderr = os.Stderr
 	out, err := cmd.Output()
 	if err != nil {
 		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(actions) != 0 {
+			// fzf exits 1 when an --expect key is pressed with nothing
+			// matching, which still reports the key and query.
+			if result, decodeErr := decodeActionOutput(out, items, actions); decodeErr == nil && result.action != "" {
+				return result, nil
+			}
+		}
 		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) {
-			return nil, ErrCancelled
+			return selection{}, ErrCancelled
 		}
 		if errors.Is(err, exec.ErrNotFound) {
-			return nil, fmt.Errorf("fzf is required for interactive selection")
+			return selection{}, fmt.Errorf("fzf is not installed; unset GROVE_PICKER or set it to builtin")
 		}
-		return nil, fmt.Errorf("fzf: %w", err)
+		return selection{}, fmt.Errorf("fzf: %w", err)
 	}
-	return decodeSelections(out, items)
+	if len(actions) != 0 {
+		return decodeActionOutput(out, items, actions)
+	}
+	keys, err := decodeSelections(out, items)
+	return selection{keys: keys}, err
+}
+
+// fzfArgs returns the fzf flags for one picker. When the preview pane is
+// asked for and can be set up, it also returns the cleanup of its key file;
+// otherwise cleanup is nil and fzf runs without a preview.
+func fzfArgs(prompt string, items []Item, multi, preview bool, actions []Action) ([]string, func()) {
+	args := append(selectionArgs(prompt, multi), actionArgs(actions)...)
+	if !preview {
+		return args, nil
+	}
+	previewFlags, cleanup, err := previewArgs(items)
+	if err != nil {
+		return args, nil
+	}
+	return append(args, previewFlags...), cleanup
 }
 
 func selectionArgs(prompt string, multi bool) []string {
@@ -63,6 +165,74 @@ func selectionArgs(prompt string, multi bool) []string {
 	return args
 }
 
+// actionArgs makes fzf end on each action key as it does on Enter, printing
+// the query and the key before the selection, and lists the keys in the header.
+func actionArgs(actions []Action) []string {
+	if len(actions) == 0 {
+		return nil
+	}
+	return []string{"--expect", strings.Join(actionKeys(actions), ","), "--print-query", "--header", actionHeader(actions)}
+}
+
+func actionKeys(actions []Action) []string {
+	keys := make([]string, 0, len(actions))
+	for _, action := range actions {
+		keys = append(keys, action.Key)
+	}
+	return keys
+}
+
+func actionHeader(actions []Action) string {
+	hints := make([]string, 0, len(actions))
+	for _, action := range actions {
+		hints = append(hints, action.Key+" "+action.Name)
+	}
+	return strings.Join(hints, " · ")
+}
+
+// PreviewCommand is the hidden grove subcommand fzf runs for the highlighted
+// item. It receives the item's index and a file of NUL-separated keys, so keys
+// never pass through fzf's field splitting or the shell.
+const PreviewCommand = "__preview"
+
+// previewArgs writes the item keys to a temporary file and returns the fzf
+// flags that preview each item by index, along with the file's cleanup. It
+// fails when grove cannot name its own executable.
+func previewArgs(items []Item) ([]string, func(), error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.CreateTemp("", "grove-preview-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.Remove(file.Name()) }
+	for _, item := range items {
+		file.WriteString(item.Key)
+		file.Write([]byte{0})
+	}
+	if err := file.Close(); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return previewCommandArgs(executable, file.Name()), cleanup, nil
+}
+
+func previewCommandArgs(executable, keysFile string) []string {
+	command := shellQuote(executable)
+	if _, disabled := os.LookupEnv("NO_COLOR"); !disabled {
+		command += " --color always"
+	}
+	command += " " + PreviewCommand + " --keys " + shellQuote(keysFile) + " {1}"
+	return []string{"--preview", command, "--preview-window", "right,50%,wrap"}
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func Interactive() bool {
 	stdin, err := os.Stdin.Stat()
 	if err != nil || stdin.Mode()&os.ModeCharDevice == 0 {
@@ -113,3 +283,34 @@ func decodeSelections(output []byte, items []Item) ([]string, error) {
 	}
 	return selected, nil
 }
+
+// decodeActionOutput reads fzf's --print-query --expect output: the query,
+// the key that ended the picker (empty for Enter), then the selection, if any.
+func decodeActionOutput(output []byte, items []Item, actions []Action) (selection, error) {
+	records := bytes.SplitN(output, []byte{0}, 3)
+	if len(records) < 2 {
+		return selection{}, fmt.Errorf("invalid fzf selection")
+	}
+	result := selection{query: string(records[0])}
+	if pressed := string(records[1]); pressed != "" {
+		for _, action := range actions {
+			if action.Key == pressed {
+				result.action = action.Name
+			}
+		}
+		if result.action == "" {
+			return selection{}, fmt.Errorf("unexpected fzf key %q", pressed)
+		}
+	}
+	if len(records) == 3 && len(bytes.TrimSuffix(records[2], []byte{0})) != 0 {
+		key, err := decodeSelection(records[2], items)
+		if err != nil {
+			return selection{}, err
+		}
+		result.keys = []string{key}
+	}
+	if len(result.keys) == 0 && result.action == "" {
+		return selection{}, ErrCancelled
+	}
+	return result, nil
+}
@@ -54,3 +54,48 @@ func TestSingleSelectionPreservesCallerOrder(t *testing.T) {
 		t.Fatalf("multi-selection args = %#v, do not want --no-sort", args)
 	}
 }
+
+func TestPreviewCommandQuotesPathsAndPassesTheIndex(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	args := previewCommandArgs("/opt/it's grove/grove", "/tmp/keys file")
+	if len(args) < 2 || args[0] != "--preview" {
+		t.Fatalf("preview args = %#v", args)
+	}
+	want := `'/opt/it'\''s grove/grove' __preview --keys '/tmp/keys file' {1}`
+	if args[1] != want {
+		t.Fatalf("preview command = %q, want %q", args[1], want)
+	}
+}
+
+func TestOnlyPreviewingPickersGetAPreviewPane(t *testing.T) {
+	items := []Item{{Key: "/repo/.wt/feat/auth", Label: "app:feat/auth"}}
+	if args, cleanup := fzfArgs("restore > ", items, false, false, nil); cleanup != nil || slices.Contains(args, "--preview") {
+		t.Fatalf("args without preview = %#v", args)
+	}
+	args, cleanup := fzfArgs("worktree > ", items, false, true, nil)
+	if cleanup == nil || !slices.Contains(args, "--preview") {
+		t.Fatalf("args with preview = %#v", args)
+	}
+	cleanup()
+}
+
+func TestDecodeActionOutputReportsQueryKeyAndSelection(t *testing.T) {
+	items := []Item{{Key: "first", Label: "one"}, {Key: "second", Label: "two"}}
+	actions := []Action{{Key: "ctrl-n", Name: "new"}, {Key: "ctrl-x", Name: "remove"}}
+
+	got, err := decodeActionOutput([]byte("tw\x00ctrl-x\x001\ttwo\x00"), items, actions)
+	if err != nil || got.action != "remove" || got.query != "tw" || !slices.Equal(got.keys, []string{"second"}) {
+		t.Fatalf("ctrl-x = %#v, %v", got, err)
+	}
+	got, err = decodeActionOutput([]byte("feat/new\x00ctrl-n\x00"), items, actions)
+	if err != nil || got.action != "new" || got.query != "feat/new" || len(got.keys) != 0 {
+		t.Fatalf("ctrl-n with no match = %#v, %v", got, err)
+	}
+	got, err = decodeActionOutput([]byte("\x00\x000\tone\x00"), items, actions)
+	if err != nil || got.action != "" || !slices.Equal(got.keys, []string{"first"}) {
+		t.Fatalf("enter = %#v, %v", got, err)
+	}
+	if args := actionArgs(actions); !slices.Contains(args, "ctrl-n,ctrl-x") || !slices.Contains(args, "--print-query") {
+		t.Fatalf("action args = %#v", args)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -53,10 +55,79 @@ func (t *Tracker) LastVisited(path string) (time.Time, bool) {
 	return info.ModTime(), true
 }
 
+// Visit is one worktree's most recent recorded visit.
+type Visit struct {
+	Path      string
+	VisitedAt time.Time
+}
+
+// Recent lists visits newest first, at most limit of them when limit is
+// positive. Markers for paths that no longer exist are skipped rather than
+// removed, and files that are not markers are ignored, so stale or damaged
+// state only shortens the list.
+func (t *Tracker) Recent(limit int) ([]Visit, error) {
+	entries, err := os.ReadDir(t.directory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading recency markers: %w", err)
+	}
+	visits := make([]Visit, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || !entry.Type().IsRegular() {
+			continue
+		}
+		markerPath := filepath.Join(t.directory, entry.Name())
+		contents, err := os.ReadFile(markerPath)
+		if err != nil {
+			continue
+		}
+		path := strings.TrimSuffix(string(contents), "\n")
+		if path == "" || t.markerPath(path) != markerPath {
+			continue
+		}
+		if _, err := os.Lstat(path); err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		visits = append(visits, Visit{Path: path, VisitedAt: info.ModTime()})
+	}
+	sort.SliceStable(visits, func(i, j int) bool {
+		if !visits[i].VisitedAt.Equal(visits[j].VisitedAt) {
+			return visits[i].VisitedAt.After(visits[j].VisitedAt)
+		}
+		return visits[i].Path < visits[j].Path
+	})
+	if limit > 0 && len(visits) > limit {
+		visits = visits[:limit]
+	}
+	return visits, nil
+}
+
 func (t *Tracker) MarkVisited(path string) error {
 	return t.markVisitedAt(path, time.Now())
 }
 
+// Move carries the last visit of a worktree that moved from one path to
+// another, so its picker rank follows it. A path never visited is a no-op.
+func (t *Tracker) Move(from, to string) error {
+	visitedAt, ok := t.LastVisited(from)
+	if !ok {
+		return nil
+	}
+	if err := t.markVisitedAt(to, visitedAt); err != nil {
+		return err
+	}
+	if err := os.Remove(t.markerPath(from)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing recency marker: %w", err)
+	}
+	return nil
+}
+
 func (t *Tracker) markVisitedAt(path string, visitedAt time.Time) error {
 	if path == "" {
 		return fmt.Errorf("worktree path is required")
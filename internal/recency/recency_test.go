@@ -51,6 +51,63 @@ func TestTrackerRefreshesExistingMarker(t *testing.T) {
 	}
 }
 
+func TestTrackerMoveCarriesVisitTime(t *testing.T) {
+	tracker := New(t.TempDir())
+	from := filepath.Join(t.TempDir(), "old")
+	to := filepath.Join(t.TempDir(), "new")
+	visitedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := tracker.markVisitedAt(from, visitedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tracker.Move(from, to); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if _, ok := tracker.LastVisited(from); ok {
+		t.Fatal("old marker remains after Move()")
+	}
+	if got, ok := tracker.LastVisited(to); !ok || !got.Equal(visitedAt) {
+		t.Fatalf("LastVisited(to) = %v, %v, want %v", got, ok, visitedAt)
+	}
+	if err := tracker.Move(from, to); err != nil {
+		t.Fatalf("Move() of unvisited path error = %v", err)
+	}
+}
+
+func TestTrackerRecentListsLiveVisitsNewestFirst(t *testing.T) {
+	tracker := New(t.TempDir())
+	if visits, err := tracker.Recent(0); err != nil || len(visits) != 0 {
+		t.Fatalf("Recent() before any visit = %#v, %v", visits, err)
+	}
+	base := t.TempDir()
+	older, newer, gone := filepath.Join(base, "older"), filepath.Join(base, "new\nline"), filepath.Join(base, "gone")
+	for _, path := range []string{older, newer} {
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now().Truncate(time.Second)
+	for path, visitedAt := range map[string]time.Time{older: now.Add(-time.Hour), newer: now.Add(-time.Minute), gone: now} {
+		if err := tracker.markVisitedAt(path, visitedAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tracker.directory, "stray"), []byte(older+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	visits, err := tracker.Recent(0)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(visits) != 2 || visits[0].Path != newer || visits[1].Path != older || !visits[1].VisitedAt.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("Recent() = %#v, want live visits newest first", visits)
+	}
+	if visits, err := tracker.Recent(1); err != nil || len(visits) != 1 || visits[0].Path != newer {
+		t.Fatalf("Recent(1) = %#v, %v", visits, err)
+	}
+}
+
 func TestTrackerConcurrentMarksKeepNewestVisit(t *testing.T) {
 	tracker := New(t.TempDir())
 	worktreePath := filepath.Join(t.TempDir(), "worktree")